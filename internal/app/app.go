@@ -2,17 +2,28 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"math/rand"
 	"time"
 
 	"github.com/zapevnik/pr-review-service/internal/app/config"
 	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/repository/memory"
 	"github.com/zapevnik/pr-review-service/internal/repository/postgres"
+	"github.com/zapevnik/pr-review-service/internal/repository/sqlite"
 	"github.com/zapevnik/pr-review-service/internal/transport/httpserver"
 	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/handlers"
 )
 
+type storage struct {
+	prRepo    review.PRRepository
+	userRepo  review.UserRepository
+	teamRepo  review.TeamRepository
+	txManager review.TxManager
+	close     func() error
+}
+
 type App struct {
 	log *slog.Logger
 	cfg *config.Config
@@ -28,41 +39,48 @@ func New(logg *slog.Logger, cfg *config.Config) *App {
 func (a *App) Run(ctx context.Context) error {
 	a.log.Info("starting application")
 
-	db, err := postgres.New(a.log, ctx, postgres.Config{
-		DSN:             a.cfg.Database.DSN(),
-		MigrationsDir:   "./migrations",
-		MaxOpenConns:    a.cfg.Database.Pool.MaxOpenConns,
-		MaxIdleConns:    a.cfg.Database.Pool.MaxIdleConns,
-		ConnMaxLifetime: a.cfg.Database.Pool.ConnMaxLifetime.Duration,
-	})
+	st, err := a.openStorage(ctx)
 	if err != nil {
-		a.log.Error("failed to init db", "error", err)
+		a.log.Error("failed to init storage", "error", err)
 		return err
 	}
 	defer func() {
-		if err := db.Close(); err != nil {
-			a.log.Info("db close error", "error", err)
+		if err := st.close(); err != nil {
+			a.log.Info("storage close error", "error", err)
 		}
 	}()
 
-	prRepo := postgres.NewPRRepo(db, a.log)
-	userRepo := postgres.NewUserRepo(db, a.log)
-	teamRepo := postgres.NewTeamRepo(db, a.log)
-
 	randSrc := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	svc := review.NewService(prRepo, userRepo, teamRepo, randSrc, a.log)
+	selectors, err := a.buildSelectors(randSrc)
+	if err != nil {
+		a.log.Error("failed to init reviewer selectors", "error", err)
+		return err
+	}
+
+	mergePolicy := review.MergePolicy{
+		RequiredApprovals:       a.cfg.Review.Merge.RequiredApprovals,
+		BlockOnChangesRequested: a.cfg.Review.Merge.BlockOnChangesRequested,
+		RequireSeniorApproval:   a.cfg.Review.Merge.RequireSeniorApproval,
+		ForceAllowed:            a.cfg.Review.Merge.ForceAllowed,
+	}
+
+	svc := review.NewService(st.prRepo, st.userRepo, st.teamRepo, st.txManager, selectors, mergePolicy, randSrc, a.log)
 
 	a.log.Info("domain service initialized successfully")
 
 	prHandler := handlers.NewPRHandler(svc, a.log)
 	teamHandler := handlers.NewTeamHandler(svc, a.log)
 	userHandler := handlers.NewUserHandler(svc, a.log)
+	codeOwnersHandler := handlers.NewCodeOwnersHandler(svc, a.log)
+	reviewerRulesHandler := handlers.NewReviewerRulesHandler(svc, a.log)
 
 	router := httpserver.NewRouter(
 		teamHandler,
 		userHandler,
 		prHandler,
+		codeOwnersHandler,
+		reviewerRulesHandler,
 	)
 
 	server := httpserver.New(
@@ -75,3 +93,88 @@ func (a *App) Run(ctx context.Context) error {
 
 	return server.Run(ctx, router)
 }
+
+func (a *App) buildSelectors(randSrc *rand.Rand) (review.Selectors, error) {
+	def, err := review.NewSelector(a.cfg.Review.Selector, randSrc)
+	if err != nil {
+		return review.Selectors{}, err
+	}
+
+	selectors := review.Selectors{
+		Default: def,
+		Teams:   make(map[string]review.ReviewerSelector, len(a.cfg.Review.TeamSelectors)),
+	}
+	for team, name := range a.cfg.Review.TeamSelectors {
+		sel, err := review.NewSelector(name, randSrc)
+		if err != nil {
+			return review.Selectors{}, fmt.Errorf("team %q: %w", team, err)
+		}
+		selectors.Teams[team] = sel
+	}
+
+	a.log.Info("reviewer selectors configured", "default", def.Name(), "team_overrides", len(selectors.Teams))
+	return selectors, nil
+}
+
+func (a *App) openStorage(ctx context.Context) (storage, error) {
+	switch a.cfg.Storage {
+	case config.StorageMemory:
+		a.log.Warn("using in-memory storage, data will be lost on restart")
+		st := memory.NewStore()
+		return storage{
+			prRepo:    memory.NewPRRepo(st),
+			userRepo:  memory.NewUserRepo(st),
+			teamRepo:  memory.NewTeamRepo(st),
+			txManager: memory.NewTxManager(st),
+			close:     func() error { return nil },
+		}, nil
+	case "", config.StorageDatabase:
+	default:
+		return storage{}, fmt.Errorf("unknown storage %q", a.cfg.Storage)
+	}
+
+	switch a.cfg.Database.Driver {
+	case config.DriverSQLite:
+		return a.openSQLite(ctx)
+	case "", config.DriverPostgres:
+	default:
+		return storage{}, fmt.Errorf("unknown database driver %q", a.cfg.Database.Driver)
+	}
+
+	db, err := postgres.New(a.log, ctx, postgres.Config{
+		DSN:             a.cfg.Database.DSN(),
+		MigrationsDir:   "./migrations",
+		MaxOpenConns:    a.cfg.Database.Pool.MaxOpenConns,
+		MaxIdleConns:    a.cfg.Database.Pool.MaxIdleConns,
+		ConnMaxLifetime: a.cfg.Database.Pool.ConnMaxLifetime.Duration,
+	})
+	if err != nil {
+		return storage{}, err
+	}
+
+	return storage{
+		prRepo:    postgres.NewPRRepo(db, a.log),
+		userRepo:  postgres.NewUserRepo(db, a.log),
+		teamRepo:  postgres.NewTeamRepo(db, a.log),
+		txManager: postgres.NewTxManager(db, a.log),
+		close:     db.Close,
+	}, nil
+}
+
+func (a *App) openSQLite(ctx context.Context) (storage, error) {
+	db, err := sqlite.New(a.log, ctx, sqlite.Config{
+		Path:          a.cfg.Database.Path,
+		MigrationsDir: "./migrations/sqlite",
+	})
+	if err != nil {
+		return storage{}, err
+	}
+
+	return storage{
+		prRepo:    sqlite.NewPRRepo(db, a.log),
+		userRepo:  sqlite.NewUserRepo(db, a.log),
+		teamRepo:  sqlite.NewTeamRepo(db, a.log),
+		txManager: sqlite.NewTxManager(db, a.log),
+		close:     db.Close,
+	}, nil
+}
@@ -26,6 +26,8 @@ type DBPool struct {
 }
 
 type Database struct {
+	Driver   string `yaml:"driver"`
+	Path     string `yaml:"path"`
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
 	User     string `yaml:"user"`
@@ -35,10 +37,35 @@ type Database struct {
 	Pool     DBPool `yaml:"pool"`
 }
 
+type MergePolicy struct {
+	RequiredApprovals       int      `yaml:"requiredApprovals"`
+	BlockOnChangesRequested bool     `yaml:"blockOnChangesRequested"`
+	RequireSeniorApproval   bool     `yaml:"requireSeniorApproval"`
+	ForceAllowed            []string `yaml:"forceAllowed"`
+}
+
+type Review struct {
+	Selector      string            `yaml:"selector"`
+	TeamSelectors map[string]string `yaml:"teamSelectors"`
+	Merge         MergePolicy       `yaml:"merge"`
+}
+
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type Config struct {
 	Env      string   `yaml:"env"`
+	Storage  string   `yaml:"storage"`
 	Server   Server   `yaml:"server"`
 	Database Database `yaml:"database"`
+	Review   Review   `yaml:"review"`
 }
 
 func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
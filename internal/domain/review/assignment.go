@@ -0,0 +1,486 @@
+package review
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// assignment — ревьюверы, подобранные для PR: команда, из которой взят
+// каждый, и пояснение выбора для истории PR.
+type assignment struct {
+	ReviewerIDs []string
+	Teams       map[string]string
+	Details     map[string]string
+	// UnmatchedLabels — метки PR, которым не соответствует ни один из
+	// назначенных ревьюверов.
+	UnmatchedLabels []string
+}
+
+func (a *assignment) add(st ReviewerStats, details string) {
+	a.ReviewerIDs = append(a.ReviewerIDs, st.UserID)
+	a.Teams[st.UserID] = st.TeamName
+	a.Details[st.UserID] = details
+}
+
+func (a assignment) has(userID string) bool {
+	_, ok := a.Teams[userID]
+	return ok
+}
+
+// assignReviewers подбирает ревьюверов для PR с учётом лимитов команды автора
+// и переопределения reviewersCount (0 — не задано; вне диапазона
+// min_reviewers..max_reviewers команды — ErrInvalidReviewersCount). Места заполняются по
+// этапам: владельцы изменённых файлов (по одному на правило владения), затем
+// ревьюверы команды автора по квотам ролей, с тегами под метки PR и остальные
+// её участники; если команды автора не хватает, те же этапы повторяются для
+// резервных команд в заданном порядке.
+func (s *Service) assignReviewers(ctx context.Context, pr PullRequest, author User, reviewersCount int) (assignment, error) {
+	p, err := s.planReviewers(ctx, pr, author, reviewersCount)
+	if err != nil {
+		return assignment{}, err
+	}
+	return p.res, nil
+}
+
+// getAuthor читает автора PR для создания, перевода из черновика и
+// предпросмотра, чтобы все три возвращали одинаковые ошибки.
+func (s *Service) getAuthor(ctx context.Context, authorID string) (User, error) {
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		s.log.Warn("failed to get author", "author_id", authorID, "error", err)
+		return User{}, err
+	}
+	return author, nil
+}
+
+// planReviewers выполняет подбор ревьюверов и возвращает picker с его
+// состоянием. При ErrNotEnoughReviewers и ErrAllReviewersAtCapacity picker
+// тоже возвращается — по нему предпросмотр объясняет, кого не хватило.
+// Автор без команды даёт ErrNotInTeam.
+func (s *Service) planReviewers(ctx context.Context, pr PullRequest, author User, reviewersCount int) (*picker, error) {
+	teamName := author.Team
+	if teamName == "" {
+		s.log.Warn("author has no team", "author_id", author.ID)
+		return nil, ErrNotInTeam
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		s.log.Error("failed to get author team", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	want, required := team.MaxReviewers, team.MinReviewers
+	if reviewersCount > 0 {
+		if reviewersCount < team.MinReviewers || reviewersCount > team.MaxReviewers {
+			s.log.Warn("reviewers count outside team limits", "pr_id", pr.ID, "team", teamName,
+				"reviewers_count", reviewersCount, "min", team.MinReviewers, "max", team.MaxReviewers)
+			return nil, ErrInvalidReviewersCount
+		}
+		want = reviewersCount
+	}
+
+	fallbacks, err := s.teamRepo.ListFallbacks(ctx, teamName)
+	if err != nil {
+		s.log.Error("failed to list fallback teams", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	quotas, err := s.teamRepo.ListRoleQuotas(ctx, teamName)
+	if err != nil {
+		s.log.Error("failed to list role quotas", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	rules, err := s.rulesFor(ctx, author)
+	if err != nil {
+		return nil, err
+	}
+
+	p := s.newPicker(pr, author, want, rules)
+	p.teams = append([]string{teamName}, fallbacks...)
+	if err := p.owners(ctx); err != nil {
+		return nil, err
+	}
+	// Резервные команды занимают только места, оставшиеся после команды
+	// автора: сначала по квотам ролей, затем кандидатами под метки и любыми.
+	for _, t := range p.teams {
+		if err := p.roles(ctx, t, quotas); err != nil {
+			return nil, err
+		}
+		if err := p.labels(ctx, t); err != nil {
+			return nil, err
+		}
+		if err := p.fill(ctx, t); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, q := range quotas {
+		if n := p.seniorityCount(q.Seniority); n < q.Count {
+			s.log.Warn("role quota not met", "pr_id", pr.ID, "seniority", q.Seniority,
+				"quota", q.Count, "assigned", n)
+		}
+	}
+
+	p.res.UnmatchedLabels = p.uncoveredLabels()
+	if len(p.res.ReviewerIDs) < required {
+		s.log.Warn("not enough reviewers", "pr_id", pr.ID, "team", teamName,
+			"required", required, "available", len(p.res.ReviewerIDs), "candidates", len(p.considered))
+		if len(p.considered) >= required {
+			return p, ErrAllReviewersAtCapacity
+		}
+		return p, ErrNotEnoughReviewers
+	}
+
+	if len(p.res.UnmatchedLabels) > 0 {
+		s.log.Warn("no reviewer matches PR labels", "pr_id", pr.ID, "labels", p.res.UnmatchedLabels)
+	}
+	return p, nil
+}
+
+// picker накапливает ревьюверов одного PR по этапам подбора и кэширует
+// нагрузку команд, чтобы каждая команда читалась один раз.
+type picker struct {
+	s      *Service
+	pr     PullRequest
+	author User
+	want   int
+	rules  authorRules
+	// teams — команда автора и её резервные команды в порядке подбора.
+	teams []string
+
+	res        assignment
+	picked     map[string]ReviewerStats
+	stats      map[string][]ReviewerStats
+	considered map[string]struct{}
+}
+
+func (s *Service) newPicker(pr PullRequest, author User, want int, rules authorRules) *picker {
+	return &picker{
+		s:          s,
+		pr:         pr,
+		author:     author,
+		want:       want,
+		rules:      rules,
+		res:        assignment{Teams: map[string]string{}, Details: map[string]string{}},
+		picked:     map[string]ReviewerStats{},
+		stats:      map[string][]ReviewerStats{},
+		considered: map[string]struct{}{},
+	}
+}
+
+func (p *picker) full() bool {
+	return len(p.res.ReviewerIDs) >= p.want
+}
+
+func (p *picker) teamStats(ctx context.Context, team string) ([]ReviewerStats, error) {
+	if stats, ok := p.stats[team]; ok {
+		return stats, nil
+	}
+	stats, err := p.s.availableReviewerStats(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	p.stats[team] = stats
+	return stats, nil
+}
+
+// candidates убирает из stats автора, уже выбранных ревьюверов и тех, кого
+// правила команды запрещают назначать на PR автора.
+func (p *picker) candidates(stats []ReviewerStats) []ReviewerStats {
+	out := make([]ReviewerStats, 0, len(stats))
+	for _, st := range stats {
+		if p.rules.isExcluded(st.UserID) {
+			continue
+		}
+		if st.UserID != p.pr.AuthorID && !p.res.has(st.UserID) {
+			out = append(out, st)
+			p.considered[st.UserID] = struct{}{}
+		}
+	}
+	return out
+}
+
+// pick выбирает до count кандидатов стратегией команды team и добавляет их
+// в результат; prefix дописывается перед пояснением стратегии. Кандидаты,
+// предпочтённые правилами команды, выбираются раньше остальных.
+func (p *picker) pick(team string, candidates []ReviewerStats, count int, prefix string) {
+	preferred, others := p.rules.split(underCapacity(candidates))
+	before := len(p.res.ReviewerIDs)
+	p.selectFrom(team, preferred, count, prefix+"preferred by rule; ")
+	p.selectFrom(team, others, count-(len(p.res.ReviewerIDs)-before), prefix)
+}
+
+func (p *picker) selectFrom(team string, candidates []ReviewerStats, count int, prefix string) {
+	if count <= 0 || len(candidates) == 0 {
+		return
+	}
+	selector := p.s.selectors.For(team)
+	selection := selector.Select(SelectionInput{
+		PR:         p.pr,
+		Author:     p.author,
+		Team:       team,
+		Candidates: candidates,
+		Count:      count,
+	})
+	p.s.log.Info("reviewers selected", "pr_id", p.pr.ID, "team", team, "selector", selector.Name(),
+		"reviewers", selection.ReviewerIDs, "reason", prefix+selection.Reason)
+
+	for _, id := range selection.ReviewerIDs {
+		for _, st := range candidates {
+			if st.UserID == id {
+				p.res.add(st, prefix+selection.Reason)
+				p.picked[id] = st
+			}
+		}
+	}
+}
+
+// owners назначает владельцев кода изменённых файлов PR: для каждого
+// затронутого правила владения, владельцы которого ещё не среди ревьюверов,
+// выбирается один доступный владелец. Владелец может быть из любой команды.
+func (p *picker) owners(ctx context.Context) error {
+	pr := p.pr
+	if pr.Repository == "" || len(pr.ChangedFiles) == 0 {
+		return nil
+	}
+
+	rules, err := p.s.prRepo.ListOwnershipRules(ctx, pr.Repository)
+	if err != nil {
+		p.s.log.Error("failed to list ownership rules", "error", err, "repository", pr.Repository)
+		return err
+	}
+	groups, err := ownerGroups(rules, pr.ChangedFiles)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		if p.full() {
+			break
+		}
+		if slices.ContainsFunc(g.Owners, p.res.has) {
+			continue
+		}
+
+		var candidates []ReviewerStats
+		for _, id := range g.Owners {
+			owner, err := p.s.userRepo.GetByID(ctx, id)
+			if err != nil {
+				p.s.log.Error("failed to get code owner", "error", err, "user_id", id)
+				return err
+			}
+			if owner.Team == "" {
+				continue
+			}
+			stats, err := p.teamStats(ctx, owner.Team)
+			if err != nil {
+				return err
+			}
+			for _, st := range p.candidates(stats) {
+				if st.UserID == id {
+					candidates = append(candidates, st)
+				}
+			}
+		}
+		p.pick(p.author.Team, candidates, 1, "code owner of "+g.Pattern+"; ")
+	}
+	return nil
+}
+
+// labels для каждой метки PR, которой не соответствует ни один из уже
+// выбранных ревьюверов, назначает кандидата с таким тегом из команды team.
+// Из подходящих предпочитаются кандидаты, закрывающие больше оставшихся
+// меток.
+func (p *picker) labels(ctx context.Context, team string) error {
+	if p.full() {
+		return nil
+	}
+	stats, err := p.teamStats(ctx, team)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if team != p.author.Team {
+		prefix = "fallback team " + team + "; "
+	}
+	for _, label := range p.pr.Labels {
+		if p.full() {
+			break
+		}
+		if p.covered(label) {
+			continue
+		}
+
+		var matching []ReviewerStats
+		for _, st := range underCapacity(p.candidates(stats)) {
+			if slices.Contains(st.Tags, label) {
+				matching = append(matching, st)
+			}
+		}
+		if len(matching) > 0 {
+			p.pick(team, p.mostLabels(matching), 1, prefix+"matches label "+label+"; ")
+		}
+	}
+	return nil
+}
+
+// roles назначает ревьюверов по квотам ролей: для каждой квоты, которую
+// уже выбранные ревьюверы не закрывают, берутся кандидаты нужного уровня из
+// команды team. Среди них предпочитаются закрывающие больше меток PR.
+// Невыполнимая квота не мешает созданию PR: места достаются следующим
+// этапам и командам.
+func (p *picker) roles(ctx context.Context, team string, quotas []RoleQuota) error {
+	if p.full() || len(quotas) == 0 {
+		return nil
+	}
+	stats, err := p.teamStats(ctx, team)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if team != p.author.Team {
+		prefix = "fallback team " + team + "; "
+	}
+	for _, q := range quotas {
+		for !p.full() && p.seniorityCount(q.Seniority) < q.Count {
+			var matching []ReviewerStats
+			for _, st := range underCapacity(p.candidates(stats)) {
+				if st.Seniority == q.Seniority {
+					matching = append(matching, st)
+				}
+			}
+			if len(matching) == 0 {
+				break
+			}
+			p.pick(team, p.mostLabels(matching), 1, prefix+"role "+string(q.Seniority)+"; ")
+		}
+	}
+	return nil
+}
+
+// fill добирает оставшиеся места из активных участников команды.
+func (p *picker) fill(ctx context.Context, team string) error {
+	if p.full() {
+		return nil
+	}
+	stats, err := p.teamStats(ctx, team)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if team != p.author.Team {
+		prefix = "fallback team " + team + "; "
+	}
+	p.pick(team, p.candidates(stats), p.want-len(p.res.ReviewerIDs), prefix)
+	return nil
+}
+
+func (p *picker) seniorityCount(seniority Seniority) int {
+	n := 0
+	for _, st := range p.picked {
+		if st.Seniority == seniority {
+			n++
+		}
+	}
+	return n
+}
+
+func (p *picker) covered(label string) bool {
+	for _, st := range p.picked {
+		if slices.Contains(st.Tags, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// mostLabels оставляет кандидатов, закрывающих больше всего ещё не
+// закрытых меток PR.
+func (p *picker) mostLabels(candidates []ReviewerStats) []ReviewerStats {
+	uncovered := p.uncoveredLabels()
+
+	var (
+		out  []ReviewerStats
+		best int
+	)
+	for _, st := range candidates {
+		n := 0
+		for _, l := range uncovered {
+			if slices.Contains(st.Tags, l) {
+				n++
+			}
+		}
+		if n > best {
+			out, best = out[:0], n
+		}
+		if n == best {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// uncoveredLabels возвращает метки PR, не закрытые выбранными ревьюверами.
+func (p *picker) uncoveredLabels() []string {
+	var out []string
+	for _, l := range p.pr.Labels {
+		if !p.covered(l) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// availableReviewerStats возвращает нагрузку активных участников команды без
+// тех, у кого сейчас идёт запланированное отсутствие.
+func (s *Service) availableReviewerStats(ctx context.Context, teamName string) ([]ReviewerStats, error) {
+	stats, err := s.prRepo.ListReviewerStats(ctx, teamName)
+	if err != nil {
+		s.log.Error("failed to list reviewer stats", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	absences, err := s.userRepo.ListTeamAbsences(ctx, teamName, now)
+	if err != nil {
+		s.log.Error("failed to list team absences", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	absent := map[string]struct{}{}
+	for _, a := range absences {
+		if a.Covers(now) {
+			absent[a.UserID] = struct{}{}
+		}
+	}
+	if len(absent) == 0 {
+		return stats, nil
+	}
+
+	available := make([]ReviewerStats, 0, len(stats))
+	for _, st := range stats {
+		if _, ok := absent[st.UserID]; ok {
+			s.log.Info("skipping absent reviewer", "user_id", st.UserID, "team", teamName)
+			continue
+		}
+		available = append(available, st)
+	}
+	return available, nil
+}
+
+// underCapacity оставляет кандидатов, которые ещё не достигли лимита OPEN-ревью.
+func underCapacity(candidates []ReviewerStats) []ReviewerStats {
+	free := make([]ReviewerStats, 0, len(candidates))
+	for _, st := range candidates {
+		if !st.AtCapacity() {
+			free = append(free, st)
+		}
+	}
+	return free
+}
@@ -0,0 +1,182 @@
+package review
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// OwnershipRule — правило владения кодом в стиле CODEOWNERS: файлы,
+// подходящие под Pattern, принадлежат пользователям Owners. Для файла
+// действует последнее подходящее правило репозитория; правило без
+// владельцев снимает владение с файлов.
+//
+// Pattern понимает синтаксис CODEOWNERS: `*`, `?`, `**`, ведущий `/`
+// привязывает шаблон к корню репозитория (как и `/` в середине), завершающий
+// `/` означает каталог со всем содержимым, `dir/*` — только файлы самого
+// каталога без вложенных.
+type OwnershipRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// Validate проверяет шаблон и список владельцев правила.
+func (r OwnershipRule) Validate() error {
+	if strings.ContainsAny(r.Pattern, " \t\n[]") ||
+		strings.HasPrefix(r.Pattern, "!") || strings.HasPrefix(r.Pattern, "#") {
+		return ErrInvalidOwnershipRules
+	}
+	if _, err := ownershipPattern(r.Pattern); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(r.Owners))
+	for _, id := range r.Owners {
+		if _, dup := seen[id]; dup || id == "" {
+			return ErrInvalidOwnershipRules
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+// ownershipPattern переводит шаблон CODEOWNERS в регулярное выражение для
+// пути файла относительно корня репозитория.
+func ownershipPattern(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, ErrInvalidOwnershipRules
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.+")
+	case strings.HasSuffix(p, "/*"):
+	default:
+		// Шаблон, совпавший с каталогом, распространяется на его содержимое.
+		b.WriteString("(?:/.+)?")
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, ErrInvalidOwnershipRules
+	}
+	return re, nil
+}
+
+// normalizeChangedFiles приводит пути к виду относительно корня репозитория
+// и убирает повторы, сохраняя порядок.
+func normalizeChangedFiles(files []string) ([]string, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(files))
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		if strings.TrimSpace(f) == "" {
+			return nil, ErrInvalidChangedFiles
+		}
+		f = strings.TrimPrefix(path.Clean("/"+f), "/")
+		if f == "" {
+			return nil, ErrInvalidChangedFiles
+		}
+		if _, dup := seen[f]; dup {
+			continue
+		}
+		seen[f] = struct{}{}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// ownerGroup — владельцы части изменённых файлов по одному правилу.
+type ownerGroup struct {
+	Pattern string
+	Owners  []string
+}
+
+// ownerGroups находит для каждого файла последнее подходящее правило и
+// возвращает затронутые правила с владельцами в порядке первых файлов.
+// Файлы без владельцев в результат не попадают.
+func ownerGroups(rules []OwnershipRule, files []string) ([]ownerGroup, error) {
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		re, err := ownershipPattern(rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		patterns[i] = re
+	}
+
+	var groups []ownerGroup
+	seen := map[int]struct{}{}
+	for _, f := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if !patterns[i].MatchString(f) {
+				continue
+			}
+			if _, ok := seen[i]; !ok && len(rules[i].Owners) > 0 {
+				seen[i] = struct{}{}
+				groups = append(groups, ownerGroup{Pattern: rules[i].Pattern, Owners: rules[i].Owners})
+			}
+			break
+		}
+	}
+	return groups, nil
+}
+
+// SetOwnershipRules заменяет правила владения кодом репозитория целиком.
+// Владельцы — user_id существующих пользователей (иначе ErrNotFound).
+func (s *Service) SetOwnershipRules(ctx context.Context, repository string, rules []OwnershipRule) ([]OwnershipRule, error) {
+	return inTx(ctx, s, func(ctx context.Context) ([]OwnershipRule, error) {
+		s.log.Info("SetOwnershipRules called", "repository", repository, "rules_count", len(rules))
+
+		if repository == "" {
+			return nil, ErrInvalidOwnershipRules
+		}
+		for _, rule := range rules {
+			if err := rule.Validate(); err != nil {
+				s.log.Warn("invalid ownership rule", "repository", repository, "pattern", rule.Pattern)
+				return nil, err
+			}
+		}
+
+		if err := s.prRepo.SetOwnershipRules(ctx, repository, rules); err != nil {
+			s.log.Error("failed to set ownership rules", "repository", repository, "error", err)
+			return nil, err
+		}
+		return s.prRepo.ListOwnershipRules(ctx, repository)
+	})
+}
+
+func (s *Service) ListOwnershipRules(ctx context.Context, repository string) ([]OwnershipRule, error) {
+	return s.prRepo.ListOwnershipRules(ctx, repository)
+}
@@ -0,0 +1,124 @@
+package review
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOwnershipPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		// Без `/` шаблон совпадает на любой глубине.
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/app/main.go", true},
+		{"*.go", "main.gox", false},
+		{"docs", "docs/readme.md", true},
+		{"docs", "src/docs/readme.md", true},
+		{"v?.txt", "v1.txt", true},
+		{"v?.txt", "v10.txt", false},
+
+		// Ведущий `/` привязывает шаблон к корню.
+		{"/docs", "docs", true},
+		{"/docs", "docs/readme.md", true},
+		{"/docs", "src/docs/readme.md", false},
+		{"/main.go", "cmd/main.go", false},
+
+		// `/` в середине тоже привязывает к корню.
+		{"src/api", "src/api/handler.go", true},
+		{"src/api", "lib/src/api/handler.go", false},
+
+		// Завершающий `/` — только каталог со всем содержимым.
+		{"build/", "build/out.bin", true},
+		{"build/", "build/a/b/out.bin", true},
+		{"build/", "pkg/build/out.bin", true},
+		{"build/", "build", false},
+		{"/build/", "pkg/build/out.bin", false},
+
+		// `dir/*` — только файлы самого каталога.
+		{"docs/*", "docs/readme.md", true},
+		{"docs/*", "docs/api/readme.md", false},
+		{"docs/*", "src/docs/readme.md", false},
+
+		// `**` — любое число каталогов.
+		{"**/logs", "logs/a.log", true},
+		{"**/logs", "a/b/logs/c.log", true},
+		{"docs/**", "docs/a/b.md", true},
+		{"docs/**", "src/docs/a.md", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/yb", false},
+	}
+
+	for _, tc := range tests {
+		re, err := ownershipPattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("ownershipPattern(%q): %v", tc.pattern, err)
+		}
+		if got := re.MatchString(tc.file); got != tc.want {
+			t.Errorf("pattern %q on %q = %v, want %v", tc.pattern, tc.file, got, tc.want)
+		}
+	}
+}
+
+func TestOwnershipPatternInvalid(t *testing.T) {
+	for _, pattern := range []string{"", "/", "//"} {
+		if _, err := ownershipPattern(pattern); err != ErrInvalidOwnershipRules {
+			t.Errorf("ownershipPattern(%q) error = %v, want %v", pattern, err, ErrInvalidOwnershipRules)
+		}
+	}
+}
+
+func TestOwnerGroups(t *testing.T) {
+	rules := []OwnershipRule{
+		{Pattern: "*", Owners: []string{"lead"}},
+		{Pattern: "*.go", Owners: []string{"gopher"}},
+		{Pattern: "/cmd/", Owners: []string{"cli", "gopher"}},
+		{Pattern: "/cmd/generated/"},
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []ownerGroup
+	}{
+		{
+			name:  "last matching rule wins",
+			files: []string{"cmd/main.go"},
+			want:  []ownerGroup{{Pattern: "/cmd/", Owners: []string{"cli", "gopher"}}},
+		},
+		{
+			name:  "groups in order of first file",
+			files: []string{"README.md", "lib/x.go", "cmd/y.go", "lib/z.go"},
+			want: []ownerGroup{
+				{Pattern: "*", Owners: []string{"lead"}},
+				{Pattern: "*.go", Owners: []string{"gopher"}},
+				{Pattern: "/cmd/", Owners: []string{"cli", "gopher"}},
+			},
+		},
+		{
+			// Последнее подходящее правило без владельцев снимает владение,
+			// более ранние правила не применяются.
+			name:  "last match without owners",
+			files: []string{"cmd/generated/api.go"},
+		},
+		{
+			name:  "no files",
+			files: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ownerGroups(rules, tc.files)
+			if err != nil {
+				t.Fatalf("ownerGroups: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("ownerGroups = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
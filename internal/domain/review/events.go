@@ -0,0 +1,64 @@
+package review
+
+import (
+	"context"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventCreated            EventType = "CREATED"
+	EventReviewerAssigned   EventType = "REVIEWER_ASSIGNED"
+	EventReviewerReassigned EventType = "REVIEWER_REASSIGNED"
+	EventReadyForReview     EventType = "READY_FOR_REVIEW"
+	EventReviewSubmitted    EventType = "REVIEW_SUBMITTED"
+	EventMerged             EventType = "MERGED"
+	EventClosed             EventType = "CLOSED"
+	EventReopened           EventType = "REOPENED"
+)
+
+// PREvent — запись в append-only истории PR. Пишется репозиторием в той же
+// транзакции, что и само изменение.
+type PREvent struct {
+	ID            int64
+	PRID          string
+	Type          EventType
+	ActorID       string
+	OldReviewerID string
+	NewReviewerID string
+	Details       string
+	CreatedAt     time.Time
+}
+
+type actorKey struct{}
+
+// WithActor сохраняет в контексте идентификатор инициатора изменения.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(actorKey{}).(string)
+	return id
+}
+
+func newEvent(ctx context.Context, prID string, typ EventType) PREvent {
+	return PREvent{
+		PRID:      prID,
+		Type:      typ,
+		ActorID:   ActorFromContext(ctx),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func assignedEvents(ctx context.Context, prID string, a assignment) []PREvent {
+	events := make([]PREvent, 0, len(a.ReviewerIDs))
+	for _, id := range a.ReviewerIDs {
+		ev := newEvent(ctx, prID, EventReviewerAssigned)
+		ev.NewReviewerID = id
+		ev.Details = a.Details[id]
+		events = append(events, ev)
+	}
+	return events
+}
@@ -0,0 +1,73 @@
+package review
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MergePolicy описывает условия, при которых OPEN PR можно смержить.
+// Нулевое значение не накладывает ограничений.
+type MergePolicy struct {
+	RequiredApprovals       int
+	BlockOnChangesRequested bool
+	// RequireSeniorApproval требует хотя бы одного APPROVED от ревьювера
+	// уровня SENIOR.
+	RequireSeniorApproval bool
+	// ForceAllowed — user_id, которым разрешён merge в обход политики.
+	ForceAllowed []string
+}
+
+// CanForce сообщает, может ли инициатор actorID смержить PR в обход
+// политики. Без инициатора force запрещён.
+func (p MergePolicy) CanForce(actorID string) bool {
+	return actorID != "" && slices.Contains(p.ForceAllowed, actorID)
+}
+
+// Evaluate возвращает список невыполненных условий; пустой список — merge
+// разрешён. levels — уровни ревьюверов PR; нужны только для
+// RequireSeniorApproval.
+func (p MergePolicy) Evaluate(pr PullRequest, levels map[string]Seniority) []string {
+	var unmet []string
+
+	approvals := 0
+	seniorApproved := false
+	var changesRequested []string
+	for _, st := range pr.ReviewerStates() {
+		switch st.State {
+		case ReviewApproved:
+			approvals++
+			if levels[st.ReviewerID] == SenioritySenior {
+				seniorApproved = true
+			}
+		case ReviewChangesRequested:
+			changesRequested = append(changesRequested, st.ReviewerID)
+		}
+	}
+
+	if approvals < p.RequiredApprovals {
+		unmet = append(unmet, fmt.Sprintf("requires %d approvals, has %d", p.RequiredApprovals, approvals))
+	}
+	if p.BlockOnChangesRequested && len(changesRequested) > 0 {
+		unmet = append(unmet, fmt.Sprintf("changes requested by %s", strings.Join(changesRequested, ", ")))
+	}
+	if p.RequireSeniorApproval && !seniorApproved {
+		unmet = append(unmet, "requires approval from a SENIOR reviewer")
+	}
+
+	return unmet
+}
+
+// MergeBlockedError возвращается MergePR, если политика merge не выполнена.
+// errors.Is(err, ErrMergeBlocked) для неё истинно.
+type MergeBlockedError struct {
+	Unmet []string
+}
+
+func (e *MergeBlockedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrMergeBlocked, strings.Join(e.Unmet, "; "))
+}
+
+func (e *MergeBlockedError) Is(target error) bool {
+	return target == ErrMergeBlocked
+}
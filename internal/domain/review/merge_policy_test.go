@@ -0,0 +1,112 @@
+package review
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func prWithReviews(states map[string]ReviewState) PullRequest {
+	pr := PullRequest{ID: "pr1", ReviewerIDs: []string{"u1", "u2", "u3"}}
+	for _, id := range pr.ReviewerIDs {
+		if st, ok := states[id]; ok {
+			pr.Reviews = append(pr.Reviews, Review{PRID: pr.ID, ReviewerID: id, State: st})
+		}
+	}
+	return pr
+}
+
+func TestMergePolicyEvaluate(t *testing.T) {
+	levels := map[string]Seniority{"u1": SeniorityJunior, "u2": SenioritySenior}
+
+	tests := []struct {
+		name   string
+		policy MergePolicy
+		states map[string]ReviewState
+		want   []string
+	}{
+		{
+			name:   "zero policy allows anything",
+			states: map[string]ReviewState{"u1": ReviewChangesRequested},
+		},
+		{
+			name:   "not enough approvals",
+			policy: MergePolicy{RequiredApprovals: 2},
+			states: map[string]ReviewState{"u1": ReviewApproved, "u2": ReviewCommented},
+			want:   []string{"requires 2 approvals, has 1"},
+		},
+		{
+			name:   "enough approvals",
+			policy: MergePolicy{RequiredApprovals: 2},
+			states: map[string]ReviewState{"u1": ReviewApproved, "u3": ReviewApproved},
+		},
+		{
+			name:   "outstanding changes requested",
+			policy: MergePolicy{BlockOnChangesRequested: true},
+			states: map[string]ReviewState{"u1": ReviewApproved, "u2": ReviewChangesRequested, "u3": ReviewChangesRequested},
+			want:   []string{"changes requested by u2, u3"},
+		},
+		{
+			name:   "changes requested ignored when not blocking",
+			policy: MergePolicy{RequiredApprovals: 1},
+			states: map[string]ReviewState{"u1": ReviewApproved, "u2": ReviewChangesRequested},
+		},
+		{
+			name:   "no senior approval",
+			policy: MergePolicy{RequireSeniorApproval: true},
+			states: map[string]ReviewState{"u1": ReviewApproved, "u2": ReviewCommented},
+			want:   []string{"requires approval from a SENIOR reviewer"},
+		},
+		{
+			name:   "senior approved",
+			policy: MergePolicy{RequireSeniorApproval: true},
+			states: map[string]ReviewState{"u2": ReviewApproved},
+		},
+		{
+			name:   "all conditions unmet",
+			policy: MergePolicy{RequiredApprovals: 1, BlockOnChangesRequested: true, RequireSeniorApproval: true},
+			states: map[string]ReviewState{"u3": ReviewChangesRequested},
+			want: []string{
+				"requires 1 approvals, has 0",
+				"changes requested by u3",
+				"requires approval from a SENIOR reviewer",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.policy.Evaluate(prWithReviews(tc.states), levels)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Evaluate = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergePolicyCanForce(t *testing.T) {
+	p := MergePolicy{ForceAllowed: []string{"lead"}}
+	for actor, want := range map[string]bool{"lead": true, "u1": false, "": false} {
+		if got := p.CanForce(actor); got != want {
+			t.Errorf("CanForce(%q) = %v, want %v", actor, got, want)
+		}
+	}
+	if (MergePolicy{}).CanForce("lead") {
+		t.Errorf("empty policy allows force")
+	}
+}
+
+func TestMergeBlockedError(t *testing.T) {
+	var err error = &MergeBlockedError{Unmet: []string{"requires 1 approvals, has 0", "changes requested by u3"}}
+
+	if !errors.Is(err, ErrMergeBlocked) {
+		t.Fatalf("errors.Is(%v, ErrMergeBlocked) = false", err)
+	}
+	if errors.Is(err, ErrConflict) {
+		t.Fatalf("errors.Is(%v, ErrConflict) = true", err)
+	}
+	want := "MERGE_BLOCKED: requires 1 approvals, has 0; changes requested by u3"
+	if err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -1,7 +1,10 @@
 package review
 
 import (
+	"context"
 	"errors"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -10,8 +13,27 @@ type PRStatus string
 const (
 	StatusOpen   PRStatus = "OPEN"
 	StatusMerged PRStatus = "MERGED"
+	StatusClosed PRStatus = "CLOSED"
+	StatusDraft  PRStatus = "DRAFT"
 )
 
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewCommented        ReviewState = "COMMENTED"
+)
+
+func (s ReviewState) Valid() bool {
+	switch s {
+	case ReviewApproved, ReviewChangesRequested, ReviewCommented:
+		return true
+	}
+	return false
+}
+
 type PullRequest struct {
 	ID          string
 	Title       string
@@ -19,11 +41,123 @@ type PullRequest struct {
 	Status      PRStatus
 	CreatedAt   time.Time
 	MergedAt    *time.Time
-	ReviewerIDs []string
+	ClosedAt    *time.Time
+	MergeForced bool
+	// ClosedFrom — статус PR (OPEN или DRAFT) перед закрытием; по нему
+	// ReopenPR восстанавливает статус. Пуст, пока PR не закрыт.
+	ClosedFrom PRStatus
+	// Repository и ChangedFiles задаются при создании PR и используются для
+	// подбора ревьюверов по владельцам кода; после создания не меняются.
+	Repository   string
+	ChangedFiles []string
+	// Labels — метки PR (например, go, sql); при назначении для каждой метки
+	// по возможности выбирается ревьювер с таким тегом.
+	Labels []string
+	// UnmatchedLabels заполняется сервисом при назначении ревьюверов (создание
+	// PR и перевод из черновика) и не хранится.
+	UnmatchedLabels []string
+	ReviewerIDs     []string
+	// ReviewerTeams — команда, из которой назначен каждый ревьювер (команда
+	// автора или резервная). Для назначений до появления резервных команд
+	// записи может не быть.
+	ReviewerTeams map[string]string
+	Reviews       []Review
+	// Version увеличивается при каждом изменении PR и используется
+	// для optimistic concurrency в PRRepository.Update.
+	Version int64
+}
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion задаёт версию PR, которую клиент ожидает изменить.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+func ExpectedVersionFromContext(ctx context.Context) (int64, bool) {
+	v, ok := ctx.Value(expectedVersionKey{}).(int64)
+	return v, ok
 }
 
+// Review — одно ревью, отправленное ревьювером. Reviews в PullRequest
+// упорядочены по времени отправки.
+type Review struct {
+	ID          int64
+	PRID        string
+	ReviewerID  string
+	State       ReviewState
+	Body        string
+	SubmittedAt time.Time
+}
+
+type ReviewerState struct {
+	ReviewerID  string
+	State       ReviewState
+	Body        string
+	SubmittedAt *time.Time
+}
+
+// ReviewerStates возвращает итоговое состояние каждого назначенного ревьювера.
+// Последнее APPROVED или CHANGES_REQUESTED определяет решение; COMMENTED
+// не отменяет ранее принятое решение.
+func (pr PullRequest) ReviewerStates() []ReviewerState {
+	latest := make(map[string]Review, len(pr.Reviews))
+	for _, rv := range pr.Reviews {
+		prev, seen := latest[rv.ReviewerID]
+		if rv.State == ReviewCommented && seen && prev.State != ReviewCommented {
+			continue
+		}
+		latest[rv.ReviewerID] = rv
+	}
+
+	states := make([]ReviewerState, 0, len(pr.ReviewerIDs))
+	for _, id := range pr.ReviewerIDs {
+		st := ReviewerState{ReviewerID: id, State: ReviewPending}
+		if rv, ok := latest[id]; ok {
+			t := rv.SubmittedAt
+			st.State = rv.State
+			st.Body = rv.Body
+			st.SubmittedAt = &t
+		}
+		states = append(states, st)
+	}
+	return states
+}
+
+const (
+	DefaultMinReviewers = 1
+	DefaultMaxReviewers = 2
+)
+
 type Team struct {
-	Name string
+	Name         string
+	MinReviewers int
+	MaxReviewers int
+	// MaxOpenReviews — лимит OPEN-ревью на участника по умолчанию; 0 — без лимита.
+	MaxOpenReviews int
+}
+
+func (t Team) ValidateReviewersLimits() error {
+	if t.MinReviewers < 0 || t.MaxReviewers < 1 || t.MinReviewers > t.MaxReviewers {
+		return ErrInvalidReviewersCount
+	}
+	return nil
+}
+
+// validateMaxOpenReviews проверяет лимит OPEN-ревью; 0 означает «не задан».
+func validateMaxOpenReviews(n int) error {
+	if n < 0 {
+		return ErrInvalidMaxOpenReviews
+	}
+	return nil
+}
+
+// CreatePROptions — параметры создания PR, не хранящиеся в самом PR.
+type CreatePROptions struct {
+	// ReviewersCount переопределяет max_reviewers команды; 0 — не задано.
+	ReviewersCount int
+	// Draft создаёт PR в статусе DRAFT без назначения ревьюверов.
+	Draft bool
 }
 
 type User struct {
@@ -31,6 +165,61 @@ type User struct {
 	Team     string
 	Name     string
 	IsActive bool
+	// MaxOpenReviews переопределяет лимит команды; 0 — действует лимит команды.
+	MaxOpenReviews int
+	// Tags — навыки пользователя (go, sql, frontend), отсортированы по
+	// алфавиту. Меняются только через UserRepository.SetTags.
+	Tags []string
+	// Seniority — уровень пользователя для квот ролей; "" — не задан.
+	Seniority Seniority
+}
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._+#-]{0,31}$`)
+
+// normalizeTags приводит теги или метки к нижнему регистру и убирает
+// повторы, сохраняя порядок.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !tagPattern.MatchString(t) {
+			return nil, ErrInvalidTags
+		}
+		if _, dup := seen[t]; dup {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// Absence — запланированное отсутствие пользователя в полуинтервале
+// [StartsAt, EndsAt). Пока оно длится, пользователь не попадает в пул
+// кандидатов на ревью, даже если IsActive == true.
+type Absence struct {
+	ID       int64
+	UserID   string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+func (a Absence) Validate() error {
+	if a.StartsAt.IsZero() || !a.EndsAt.After(a.StartsAt) {
+		return ErrInvalidAbsence
+	}
+	return nil
+}
+
+// Covers сообщает, приходится ли момент t на отсутствие.
+func (a Absence) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
 }
 
 type ReviewerStats struct {
@@ -38,14 +227,50 @@ type ReviewerStats struct {
 	Username        string
 	TeamName        string
 	AssignedOpenPRs int
+	// MaxOpenReviews — действующий лимит пользователя (его собственный или
+	// лимит команды); 0 — без лимита.
+	MaxOpenReviews int
+	Tags           []string
+	Seniority      Seniority
+	// LastAssigned — номер последнего события назначения пользователя
+	// ревьювером (REVIEWER_ASSIGNED или REVIEWER_REASSIGNED): чем больше,
+	// тем позже; 0 — не назначался. По нему RoundRobinSelector ведёт очередь.
+	LastAssigned int64
+}
+
+// AtCapacity сообщает, что пользователь уже набрал максимум OPEN-ревью.
+func (st ReviewerStats) AtCapacity() bool {
+	return st.MaxOpenReviews > 0 && st.AssignedOpenPRs >= st.MaxOpenReviews
 }
 
 var (
 	ErrTeamExists        = errors.New("TEAM_EXISTS")
 	ErrPRExists          = errors.New("PR_EXISTS")
 	ErrPRMerged          = errors.New("PR_MERGED")
+	ErrPRClosed          = errors.New("PR_CLOSED")
+	ErrPRDraft           = errors.New("PR_DRAFT")
 	ErrNotAssigned       = errors.New("NOT_ASSIGNED")
 	ErrNoCandidate       = errors.New("NO_CANDIDATE")
 	ErrNotFound          = errors.New("NOT_FOUND")
+	ErrConflict          = errors.New("CONFLICT")
 	ErrUserInAnotherTeam = errors.New("USER_IN_ANOTHER_TEAM")
+	ErrNotInTeam         = errors.New("NOT_IN_TEAM")
+	ErrTeamNotEmpty      = errors.New("TEAM_NOT_EMPTY")
+
+	ErrInvalidReviewersCount = errors.New("INVALID_REVIEWERS_COUNT")
+	ErrNotEnoughReviewers    = errors.New("NOT_ENOUGH_REVIEWERS")
+	ErrInvalidReviewState    = errors.New("INVALID_REVIEW_STATE")
+	ErrMergeBlocked          = errors.New("MERGE_BLOCKED")
+	ErrForceNotAllowed       = errors.New("FORCE_NOT_ALLOWED")
+	ErrInvalidAbsence        = errors.New("INVALID_ABSENCE")
+
+	ErrInvalidMaxOpenReviews  = errors.New("INVALID_MAX_OPEN_REVIEWS")
+	ErrAllReviewersAtCapacity = errors.New("ALL_REVIEWERS_AT_CAPACITY")
+	ErrInvalidFallbackTeams   = errors.New("INVALID_FALLBACK_TEAMS")
+	ErrInvalidOwnershipRules  = errors.New("INVALID_OWNERSHIP_RULES")
+	ErrInvalidChangedFiles    = errors.New("INVALID_CHANGED_FILES")
+	ErrInvalidTags            = errors.New("INVALID_TAGS")
+	ErrInvalidSeniority       = errors.New("INVALID_SENIORITY")
+	ErrInvalidRoleQuotas      = errors.New("INVALID_ROLE_QUOTAS")
+	ErrInvalidReviewerRules   = errors.New("INVALID_REVIEWER_RULES")
 )
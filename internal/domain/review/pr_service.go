@@ -2,63 +2,168 @@ package review
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 )
 
-func (s *Service) CreatePR(ctx context.Context, pr PullRequest) (PullRequest, error) {
-	s.log.Info("CreatePR called", "author_id", pr.AuthorID, "title", pr.Title)
+func (s *Service) CreatePR(ctx context.Context, pr PullRequest, opts CreatePROptions) (PullRequest, error) {
+	return inTx(ctx, s, func(ctx context.Context) (PullRequest, error) {
+		return s.createPR(ctx, pr, opts)
+	})
+}
 
-	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+func (s *Service) createPR(ctx context.Context, pr PullRequest, opts CreatePROptions) (PullRequest, error) {
+	s.log.Info("CreatePR called", "author_id", pr.AuthorID, "title", pr.Title,
+		"reviewers_count", opts.ReviewersCount, "draft", opts.Draft,
+		"repository", pr.Repository, "changed_files", len(pr.ChangedFiles), "labels", pr.Labels)
+
+	pr, err := s.normalizeNewPR(pr, opts)
 	if err != nil {
-		s.log.Error("failed to get author", "error", err, "author_id", pr.AuthorID)
 		return PullRequest{}, err
 	}
 
-	pr.Status = StatusOpen
-	teamName := author.Team
-
-	stats, err := s.prRepo.ListReviewerStats(ctx, teamName)
+	author, err := s.getAuthor(ctx, pr.AuthorID)
 	if err != nil {
-		s.log.Error("failed to list reviewer stats", "error", err, "team", teamName)
 		return PullRequest{}, err
 	}
 
-	candidates := make([]ReviewerStats, 0, len(stats))
-	for _, r := range stats {
-		if r.UserID != pr.AuthorID {
-			candidates = append(candidates, r)
+	events := []PREvent{newEvent(ctx, pr.ID, EventCreated)}
+
+	var unmatched []string
+	if opts.Draft {
+		pr.Status = StatusDraft
+		pr.ReviewerIDs = nil
+		events[0].Details = "draft"
+	} else {
+		pr.Status = StatusOpen
+		assigned, err := s.assignReviewers(ctx, pr, author, opts.ReviewersCount)
+		if err != nil {
+			return PullRequest{}, err
 		}
+		pr.ReviewerIDs = assigned.ReviewerIDs
+		pr.ReviewerTeams = assigned.Teams
+		unmatched = assigned.UnmatchedLabels
+		events = append(events, assignedEvents(ctx, pr.ID, assigned)...)
 	}
 
-	if len(candidates) > 1 {
-		s.randSrc.Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
-		})
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = time.Now().UTC()
 	}
-	if len(candidates) > 2 {
-		candidates = candidates[:2]
+
+	created, err := s.prRepo.Create(ctx, pr, events...)
+	if err != nil {
+		s.log.Error("failed to create PR", "error", err, "pr_id", pr.ID)
+		return PullRequest{}, err
 	}
 
-	pr.ReviewerIDs = make([]string, 0, len(candidates))
-	for _, c := range candidates {
-		pr.ReviewerIDs = append(pr.ReviewerIDs, c.UserID)
+	created.UnmatchedLabels = unmatched
+
+	s.log.Info("PR created successfully", "pr_id", created.ID, "status", created.Status, "reviewers", created.ReviewerIDs)
+	return created, nil
+}
+
+// normalizeNewPR проверяет параметры нового PR и приводит к каноническому
+// виду изменённые файлы и метки.
+func (s *Service) normalizeNewPR(pr PullRequest, opts CreatePROptions) (PullRequest, error) {
+	if opts.ReviewersCount < 0 {
+		return PullRequest{}, ErrInvalidReviewersCount
 	}
 
-	if pr.CreatedAt.IsZero() {
-		pr.CreatedAt = time.Now().UTC()
+	files, err := normalizeChangedFiles(pr.ChangedFiles)
+	if err != nil {
+		s.log.Warn("invalid changed files", "pr_id", pr.ID, "error", err)
+		return PullRequest{}, err
 	}
+	pr.ChangedFiles = files
 
-	created, err := s.prRepo.Create(ctx, pr)
+	labels, err := normalizeTags(pr.Labels)
 	if err != nil {
-		s.log.Error("failed to create PR", "error", err, "pr_id", pr.ID)
+		s.log.Warn("invalid PR labels", "pr_id", pr.ID, "error", err)
 		return PullRequest{}, err
 	}
+	pr.Labels = labels
+	return pr, nil
+}
 
-	s.log.Info("PR created successfully", "pr_id", created.ID, "reviewers", created.ReviewerIDs)
-	return created, nil
+// ReadyPR переводит DRAFT PR в OPEN и назначает ревьюверов по текущей нагрузке.
+// Для уже открытого PR операция идемпотентна.
+func (s *Service) ReadyPR(ctx context.Context, prID string, reviewersCount int) (PullRequest, error) {
+	return inTx(ctx, s, func(ctx context.Context) (PullRequest, error) {
+		return s.readyPR(ctx, prID, reviewersCount)
+	})
+}
+
+func (s *Service) readyPR(ctx context.Context, prID string, reviewersCount int) (PullRequest, error) {
+	s.log.Info("ReadyPR called", "pr_id", prID, "reviewers_count", reviewersCount)
+
+	if reviewersCount < 0 {
+		return PullRequest{}, ErrInvalidReviewersCount
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		s.log.Error("failed to get PR", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+	if err := s.checkVersion(ctx, pr); err != nil {
+		return PullRequest{}, err
+	}
+
+	switch pr.Status {
+	case StatusMerged:
+		s.log.Warn("cannot mark merged PR as ready", "pr_id", prID)
+		return PullRequest{}, ErrPRMerged
+	case StatusClosed:
+		s.log.Warn("cannot mark closed PR as ready", "pr_id", prID)
+		return PullRequest{}, ErrPRClosed
+	case StatusOpen:
+		s.log.Info("PR already ready for review", "pr_id", prID)
+		return pr, nil
+	}
+
+	author, err := s.getAuthor(ctx, pr.AuthorID)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	assigned, err := s.assignReviewers(ctx, pr, author, reviewersCount)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	pr.ReviewerIDs = assigned.ReviewerIDs
+	pr.ReviewerTeams = assigned.Teams
+	pr.Status = StatusOpen
+
+	events := []PREvent{newEvent(ctx, pr.ID, EventReadyForReview)}
+	events = append(events, assignedEvents(ctx, pr.ID, assigned)...)
+
+	updated, err := s.prRepo.Update(ctx, pr, events...)
+	if err != nil {
+		s.log.Error("failed to mark PR as ready", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+
+	updated.UnmatchedLabels = assigned.UnmatchedLabels
+
+	s.log.Info("PR ready for review", "pr_id", prID, "reviewers", updated.ReviewerIDs)
+	return updated, nil
 }
 
 func (s *Service) ReassignReviewer(ctx context.Context, prID string, reviewerOldID string) (PullRequest, string, error) {
+	var newID string
+	pr, err := inTx(ctx, s, func(ctx context.Context) (PullRequest, error) {
+		pr, id, err := s.reassignReviewer(ctx, prID, reviewerOldID)
+		newID = id
+		return pr, err
+	})
+	if err != nil {
+		return PullRequest{}, "", err
+	}
+	return pr, newID, nil
+}
+
+func (s *Service) reassignReviewer(ctx context.Context, prID string, reviewerOldID string) (PullRequest, string, error) {
 	s.log.Info("ReassignReviewer called", "pr_id", prID, "old_reviewer", reviewerOldID)
 
 	pr, err := s.prRepo.GetByID(ctx, prID)
@@ -66,11 +171,22 @@ func (s *Service) ReassignReviewer(ctx context.Context, prID string, reviewerOld
 		s.log.Error("failed to get PR", "error", err, "pr_id", prID)
 		return PullRequest{}, "", err
 	}
+	if err := s.checkVersion(ctx, pr); err != nil {
+		return PullRequest{}, "", err
+	}
 
 	if pr.Status == StatusMerged {
 		s.log.Warn("cannot reassign reviewer for merged PR", "pr_id", prID)
 		return PullRequest{}, "", ErrPRMerged
 	}
+	if pr.Status == StatusClosed {
+		s.log.Warn("cannot reassign reviewer for closed PR", "pr_id", prID)
+		return PullRequest{}, "", ErrPRClosed
+	}
+	if pr.Status == StatusDraft {
+		s.log.Warn("cannot reassign reviewer for draft PR", "pr_id", prID)
+		return PullRequest{}, "", ErrPRDraft
+	}
 
 	found := false
 	for _, id := range pr.ReviewerIDs {
@@ -90,44 +206,28 @@ func (s *Service) ReassignReviewer(ctx context.Context, prID string, reviewerOld
 		return PullRequest{}, "", err
 	}
 
-	teamMembers, err := s.userRepo.ListByTeam(ctx, oldReviewer.Team)
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
 	if err != nil {
-		s.log.Error("failed to list team members", "error", err, "team", oldReviewer.Team)
+		s.log.Error("failed to get PR author", "error", err, "author_id", pr.AuthorID)
 		return PullRequest{}, "", err
 	}
 
-	current := map[string]struct{}{}
-	for _, id := range pr.ReviewerIDs {
-		current[id] = struct{}{}
-	}
-
-	candidates := make([]User, 0, len(teamMembers))
-	for _, u := range teamMembers {
-		if !u.IsActive || u.ID == pr.AuthorID {
-			continue
-		}
-		if _, exists := current[u.ID]; exists {
-			continue
-		}
-		candidates = append(candidates, u)
-	}
-
-	if len(candidates) == 0 {
-		s.log.Warn("no candidate to reassign", "pr_id", prID)
-		return PullRequest{}, "", ErrNoCandidate
+	// Замена ищется в команде уходящего ревьювера, затем в команде автора и её
+	// резервных командах. Ревьювер, выведенный из команды, заменяется сразу
+	// из команды автора.
+	team := oldReviewer.Team
+	if team == "" {
+		team = author.Team
 	}
 
-	newID := candidates[s.randSrc.Intn(len(candidates))].ID
-	for i, id := range pr.ReviewerIDs {
-		if id == reviewerOldID {
-			pr.ReviewerIDs[i] = newID
-			break
-		}
+	selection, newTeam, err := s.pickReplacement(ctx, pr, team, author.Team)
+	if err != nil {
+		return PullRequest{}, "", err
 	}
 
-	updated, err := s.prRepo.Update(ctx, pr)
+	newID := selection.ReviewerIDs[0]
+	updated, err := s.replaceReviewer(ctx, pr, reviewerOldID, newID, newTeam, selection.Reason)
 	if err != nil {
-		s.log.Error("failed to update PR with new reviewer", "error", err, "pr_id", prID)
 		return PullRequest{}, "", err
 	}
 
@@ -135,14 +235,32 @@ func (s *Service) ReassignReviewer(ctx context.Context, prID string, reviewerOld
 	return updated, newID, nil
 }
 
-func (s *Service) MergePR(ctx context.Context, prID string) (PullRequest, error) {
-	s.log.Info("MergePR called", "pr_id", prID)
+// MergePR мержит PR, если выполнена политика merge. force=true обходит
+// политику, если инициатор из контекста входит в MergePolicy.ForceAllowed
+// (иначе ErrForceNotAllowed); такой merge помечается в PR флагом MergeForced.
+func (s *Service) MergePR(ctx context.Context, prID string, force bool) (PullRequest, error) {
+	return inTx(ctx, s, func(ctx context.Context) (PullRequest, error) {
+		return s.mergePR(ctx, prID, force)
+	})
+}
+
+func (s *Service) mergePR(ctx context.Context, prID string, force bool) (PullRequest, error) {
+	s.log.Info("MergePR called", "pr_id", prID, "force", force)
+
+	actor := ActorFromContext(ctx)
+	if force && !s.mergePol.CanForce(actor) {
+		s.log.Warn("force merge not allowed", "pr_id", prID, "actor_id", actor)
+		return PullRequest{}, ErrForceNotAllowed
+	}
 
 	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		s.log.Error("failed to get PR", "error", err, "pr_id", prID)
 		return PullRequest{}, err
 	}
+	if err := s.checkVersion(ctx, pr); err != nil {
+		return PullRequest{}, err
+	}
 
 	if pr.Status == StatusMerged {
 		s.log.Info("PR already merged", "pr_id", prID)
@@ -154,11 +272,36 @@ func (s *Service) MergePR(ctx context.Context, prID string) (PullRequest, error)
 		return pr, nil
 	}
 
+	if pr.Status == StatusClosed {
+		s.log.Warn("cannot merge closed PR", "pr_id", prID)
+		return PullRequest{}, ErrPRClosed
+	}
+	if pr.Status == StatusDraft {
+		s.log.Warn("cannot merge draft PR", "pr_id", prID)
+		return PullRequest{}, ErrPRDraft
+	}
+
+	ev := newEvent(ctx, pr.ID, EventMerged)
+
+	levels, err := s.reviewerLevels(ctx, pr)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	if unmet := s.mergePol.Evaluate(pr, levels); len(unmet) > 0 {
+		if !force {
+			s.log.Warn("merge blocked by policy", "pr_id", prID, "unmet", unmet)
+			return PullRequest{}, &MergeBlockedError{Unmet: unmet}
+		}
+		s.log.Warn("merge policy bypassed with force", "pr_id", prID, "actor_id", actor, "unmet", unmet)
+		pr.MergeForced = true
+		ev.Details = "forced by " + actor + ": " + strings.Join(unmet, "; ")
+	}
+
 	pr.Status = StatusMerged
 	t := time.Now().UTC()
 	pr.MergedAt = &t
 
-	updated, err := s.prRepo.Update(ctx, pr)
+	updated, err := s.prRepo.Update(ctx, pr, ev)
 	if err != nil {
 		s.log.Error("failed to merge PR", "error", err, "pr_id", prID)
 		return PullRequest{}, err
@@ -167,3 +310,209 @@ func (s *Service) MergePR(ctx context.Context, prID string) (PullRequest, error)
 	s.log.Info("PR merged successfully", "pr_id", prID)
 	return updated, nil
 }
+
+// reviewerLevels загружает уровни ревьюверов PR, если их требует политика merge.
+func (s *Service) reviewerLevels(ctx context.Context, pr PullRequest) (map[string]Seniority, error) {
+	if !s.mergePol.RequireSeniorApproval {
+		return nil, nil
+	}
+	levels := make(map[string]Seniority, len(pr.ReviewerIDs))
+	for _, id := range pr.ReviewerIDs {
+		u, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			s.log.Error("failed to get reviewer", "error", err, "pr_id", pr.ID, "user_id", id)
+			return nil, err
+		}
+		levels[id] = u.Seniority
+	}
+	return levels, nil
+}
+
+// ClosePR закрывает PR без merge. Повторное закрытие идемпотентно.
+func (s *Service) ClosePR(ctx context.Context, prID string) (PullRequest, error) {
+	return inTx(ctx, s, func(ctx context.Context) (PullRequest, error) {
+		return s.closePR(ctx, prID)
+	})
+}
+
+func (s *Service) closePR(ctx context.Context, prID string) (PullRequest, error) {
+	s.log.Info("ClosePR called", "pr_id", prID)
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		s.log.Error("failed to get PR", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+	if err := s.checkVersion(ctx, pr); err != nil {
+		return PullRequest{}, err
+	}
+
+	switch pr.Status {
+	case StatusMerged:
+		s.log.Warn("cannot close merged PR", "pr_id", prID)
+		return PullRequest{}, ErrPRMerged
+	case StatusClosed:
+		s.log.Info("PR already closed", "pr_id", prID)
+		return pr, nil
+	}
+
+	pr.ClosedFrom = pr.Status
+	pr.Status = StatusClosed
+	t := time.Now().UTC()
+	pr.ClosedAt = &t
+
+	updated, err := s.prRepo.Update(ctx, pr, newEvent(ctx, pr.ID, EventClosed))
+	if err != nil {
+		s.log.Error("failed to close PR", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+
+	s.log.Info("PR closed successfully", "pr_id", prID)
+	return updated, nil
+}
+
+// ReopenPR возвращает закрытый PR в статус, который был у него перед
+// закрытием (OPEN или DRAFT), с прежними ревьюверами. Смерженный PR
+// переоткрыть нельзя.
+func (s *Service) ReopenPR(ctx context.Context, prID string) (PullRequest, error) {
+	return inTx(ctx, s, func(ctx context.Context) (PullRequest, error) {
+		return s.reopenPR(ctx, prID)
+	})
+}
+
+func (s *Service) reopenPR(ctx context.Context, prID string) (PullRequest, error) {
+	s.log.Info("ReopenPR called", "pr_id", prID)
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		s.log.Error("failed to get PR", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+	if err := s.checkVersion(ctx, pr); err != nil {
+		return PullRequest{}, err
+	}
+
+	switch pr.Status {
+	case StatusMerged:
+		s.log.Warn("cannot reopen merged PR", "pr_id", prID)
+		return PullRequest{}, ErrPRMerged
+	case StatusOpen, StatusDraft:
+		s.log.Info("PR already open", "pr_id", prID)
+		return pr, nil
+	}
+
+	pr.Status = pr.ClosedFrom
+	if pr.Status == "" {
+		pr.Status = StatusOpen
+	}
+	pr.ClosedFrom = ""
+	pr.ClosedAt = nil
+
+	updated, err := s.prRepo.Update(ctx, pr, newEvent(ctx, pr.ID, EventReopened))
+	if err != nil {
+		s.log.Error("failed to reopen PR", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+
+	s.log.Info("PR reopened successfully", "pr_id", prID)
+	return updated, nil
+}
+
+func (s *Service) SubmitReview(ctx context.Context, prID, reviewerID string, state ReviewState, body string) (PullRequest, error) {
+	return inTx(ctx, s, func(ctx context.Context) (PullRequest, error) {
+		return s.submitReview(ctx, prID, reviewerID, state, body)
+	})
+}
+
+func (s *Service) submitReview(ctx context.Context, prID, reviewerID string, state ReviewState, body string) (PullRequest, error) {
+	s.log.Info("SubmitReview called", "pr_id", prID, "reviewer_id", reviewerID, "state", state)
+
+	if !state.Valid() {
+		s.log.Warn("invalid review state", "pr_id", prID, "state", state)
+		return PullRequest{}, ErrInvalidReviewState
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		s.log.Error("failed to get PR", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+	if err := s.checkVersion(ctx, pr); err != nil {
+		return PullRequest{}, err
+	}
+
+	if pr.Status == StatusMerged {
+		s.log.Warn("cannot review merged PR", "pr_id", prID)
+		return PullRequest{}, ErrPRMerged
+	}
+	if pr.Status == StatusClosed {
+		s.log.Warn("cannot review closed PR", "pr_id", prID)
+		return PullRequest{}, ErrPRClosed
+	}
+	if pr.Status == StatusDraft {
+		s.log.Warn("cannot review draft PR", "pr_id", prID)
+		return PullRequest{}, ErrPRDraft
+	}
+
+	if !slices.Contains(pr.ReviewerIDs, reviewerID) {
+		s.log.Warn("reviewer not assigned to PR", "pr_id", prID, "reviewer_id", reviewerID)
+		return PullRequest{}, ErrNotAssigned
+	}
+
+	ev := newEvent(ctx, prID, EventReviewSubmitted)
+	if ev.ActorID == "" {
+		ev.ActorID = reviewerID
+	}
+	ev.Details = string(state)
+	if body != "" {
+		ev.Details += ": " + body
+	}
+
+	if _, err := s.prRepo.AddReview(ctx, Review{
+		PRID:        prID,
+		ReviewerID:  reviewerID,
+		State:       state,
+		Body:        body,
+		SubmittedAt: ev.CreatedAt,
+	}, pr.Version, ev); err != nil {
+		s.log.Error("failed to save review", "error", err, "pr_id", prID, "reviewer_id", reviewerID)
+		return PullRequest{}, err
+	}
+
+	pr, err = s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		s.log.Error("failed to reload PR after review", "error", err, "pr_id", prID)
+		return PullRequest{}, err
+	}
+
+	s.log.Info("review submitted successfully", "pr_id", prID, "reviewer_id", reviewerID, "state", state)
+	return pr, nil
+}
+
+func (s *Service) GetPRHistory(ctx context.Context, prID string) ([]PREvent, error) {
+	s.log.Info("GetPRHistory called", "pr_id", prID)
+
+	if _, err := s.prRepo.GetByID(ctx, prID); err != nil {
+		s.log.Error("failed to get PR", "error", err, "pr_id", prID)
+		return nil, err
+	}
+
+	events, err := s.prRepo.ListEvents(ctx, prID)
+	if err != nil {
+		s.log.Error("failed to list PR events", "error", err, "pr_id", prID)
+		return nil, err
+	}
+
+	s.log.Info("GetPRHistory completed", "pr_id", prID, "count", len(events))
+	return events, nil
+}
+
+// checkVersion сверяет версию PR с ожидаемой из контекста (If-Match / expected_version).
+func (s *Service) checkVersion(ctx context.Context, pr PullRequest) error {
+	expected, ok := ExpectedVersionFromContext(ctx)
+	if !ok || expected == pr.Version {
+		return nil
+	}
+	s.log.Warn("PR version mismatch", "pr_id", pr.ID, "expected", expected, "actual", pr.Version)
+	return ErrConflict
+}
@@ -0,0 +1,168 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CandidateStatus — итог подбора для одного кандидата в предпросмотре.
+type CandidateStatus string
+
+const (
+	CandidateSelected       CandidateStatus = "SELECTED"
+	CandidateNotSelected    CandidateStatus = "NOT_SELECTED"
+	CandidateAuthor         CandidateStatus = "AUTHOR"
+	CandidateInactive       CandidateStatus = "INACTIVE"
+	CandidateAbsent         CandidateStatus = "ABSENT"
+	CandidateAtCapacity     CandidateStatus = "AT_CAPACITY"
+	CandidateExcludedByRule CandidateStatus = "EXCLUDED_BY_RULE"
+)
+
+// CandidateTrace — участник команды автора или её резерва (либо выбранный
+// владелец кода из другой команды) и причина, по которой он выбран или нет.
+// OpenReviews и MaxOpenReviews берутся из ListReviewerStats и равны нулю
+// для неактивных пользователей.
+type CandidateTrace struct {
+	UserID         string
+	TeamName       string
+	OpenReviews    int
+	MaxOpenReviews int
+	Status         CandidateStatus
+	Details        string
+}
+
+// AssignmentPreview — результат подбора ревьюверов для ещё не созданного
+// PR. Err — ErrNotEnoughReviewers или ErrAllReviewersAtCapacity, с которой
+// CreatePR отклонил бы такой PR; ReviewerIDs тогда содержат тех, кого
+// удалось подобрать.
+type AssignmentPreview struct {
+	AuthorID        string
+	TeamName        string
+	ReviewerIDs     []string
+	ReviewerTeams   map[string]string
+	UnmatchedLabels []string
+	Candidates      []CandidateTrace
+	Err             error
+}
+
+// PreviewAssignment выполняет подбор ревьюверов так же, как CreatePR, но
+// ничего не сохраняет. Стратегия random при создании PR может выбрать
+// других кандидатов.
+func (s *Service) PreviewAssignment(ctx context.Context, pr PullRequest, opts CreatePROptions) (AssignmentPreview, error) {
+	return inTx(ctx, s, func(ctx context.Context) (AssignmentPreview, error) {
+		s.log.Info("PreviewAssignment called", "author_id", pr.AuthorID, "reviewers_count", opts.ReviewersCount,
+			"repository", pr.Repository, "changed_files", len(pr.ChangedFiles), "labels", pr.Labels)
+
+		pr, err := s.normalizeNewPR(pr, opts)
+		if err != nil {
+			return AssignmentPreview{}, err
+		}
+
+		author, err := s.getAuthor(ctx, pr.AuthorID)
+		if err != nil {
+			return AssignmentPreview{}, err
+		}
+
+		p, err := s.planReviewers(ctx, pr, author, opts.ReviewersCount)
+		res := AssignmentPreview{AuthorID: author.ID, TeamName: author.Team}
+		switch {
+		case errors.Is(err, ErrNotEnoughReviewers), errors.Is(err, ErrAllReviewersAtCapacity):
+			res.Err = err
+		case err != nil:
+			return AssignmentPreview{}, err
+		}
+
+		res.ReviewerIDs = p.res.ReviewerIDs
+		res.ReviewerTeams = p.res.Teams
+		res.UnmatchedLabels = p.res.UnmatchedLabels
+		if res.Candidates, err = s.traceCandidates(ctx, p); err != nil {
+			return AssignmentPreview{}, err
+		}
+		return res, nil
+	})
+}
+
+// traceCandidates объясняет итог подбора для всех участников команд пула
+// в порядке подбора, затем для выбранных ревьюверов из других команд.
+func (s *Service) traceCandidates(ctx context.Context, p *picker) ([]CandidateTrace, error) {
+	var out []CandidateTrace
+	seen := map[string]struct{}{}
+
+	for _, team := range p.teams {
+		users, err := s.userRepo.ListByTeam(ctx, team)
+		if err != nil {
+			s.log.Error("failed to list team users", "error", err, "team", team)
+			return nil, err
+		}
+		stats, err := s.prRepo.ListReviewerStats(ctx, team)
+		if err != nil {
+			s.log.Error("failed to list reviewer stats", "error", err, "team", team)
+			return nil, err
+		}
+		available, err := p.teamStats(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+
+		load := make(map[string]ReviewerStats, len(stats))
+		for _, st := range stats {
+			load[st.UserID] = st
+		}
+		free := make(map[string]struct{}, len(available))
+		for _, st := range available {
+			free[st.UserID] = struct{}{}
+		}
+
+		for _, u := range users {
+			seen[u.ID] = struct{}{}
+			st := load[u.ID]
+			tr := CandidateTrace{
+				UserID:         u.ID,
+				TeamName:       team,
+				OpenReviews:    st.AssignedOpenPRs,
+				MaxOpenReviews: st.MaxOpenReviews,
+			}
+			_, isFree := free[u.ID]
+			decision, _ := p.rules.check(u.ID)
+
+			switch {
+			case u.ID == p.author.ID:
+				tr.Status, tr.Details = CandidateAuthor, "author of the PR"
+			case !u.IsActive:
+				tr.Status, tr.Details = CandidateInactive, "user is inactive"
+			case p.res.has(u.ID):
+				tr.Status, tr.Details = CandidateSelected, p.res.Details[u.ID]
+			case decision == DecisionExcluded:
+				tr.Status, tr.Details = CandidateExcludedByRule, "excluded by rule for author "+p.author.ID
+			case !isFree:
+				tr.Status, tr.Details = CandidateAbsent, "planned absence in progress"
+			case st.AtCapacity():
+				tr.Status = CandidateAtCapacity
+				tr.Details = fmt.Sprintf("open reviews %d of %d", st.AssignedOpenPRs, st.MaxOpenReviews)
+			default:
+				tr.Status = CandidateNotSelected
+				if decision == DecisionPreferred {
+					tr.Details = "preferred by rule"
+				}
+			}
+			out = append(out, tr)
+		}
+	}
+
+	for _, id := range p.res.ReviewerIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		st := p.picked[id]
+		out = append(out, CandidateTrace{
+			UserID:         id,
+			TeamName:       st.TeamName,
+			OpenReviews:    st.AssignedOpenPRs,
+			MaxOpenReviews: st.MaxOpenReviews,
+			Status:         CandidateSelected,
+			Details:        p.res.Details[id],
+		})
+	}
+	return out, nil
+}
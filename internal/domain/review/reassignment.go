@@ -0,0 +1,286 @@
+package review
+
+import (
+	"context"
+)
+
+// Reassignment — замена ревьювера в одном PR при перераспределении ревью.
+type Reassignment struct {
+	PRID          string
+	OldReviewerID string
+	NewReviewerID string
+}
+
+// ReassignmentFailure — PR, для которого замена не нашлась; ревьювер
+// остаётся назначенным, Reason содержит код ошибки.
+type ReassignmentFailure struct {
+	PRID       string
+	ReviewerID string
+	Reason     string
+}
+
+// ReassignmentReport — итог перераспределения открытых ревью пользователя.
+type ReassignmentReport struct {
+	Reassigned []Reassignment
+	Failed     []ReassignmentFailure
+}
+
+// ReviewerSwap — замена ревьювера для PRRepository.ReplaceReviewers.
+// Version — версия PR, на которой основана замена; у всех замен одного PR
+// она совпадает. NewReviewerTeam — команда, из которой взят новый ревьювер.
+type ReviewerSwap struct {
+	PRID            string
+	Version         int64
+	OldReviewerID   string
+	NewReviewerID   string
+	NewReviewerTeam string
+}
+
+// statsLoader возвращает доступных кандидатов команды; по умолчанию это
+// availableReviewerStats, пакетные операции подставляют кэш с локально
+// учтённой нагрузкой.
+type statsLoader func(ctx context.Context, team string) ([]ReviewerStats, error)
+
+// selectReplacement выбирает одного ревьювера из кандидатов stats команды
+// team на замену в PR. Автор, текущие ревьюверы PR и исключённые правилами в
+// пул не попадают, предпочтённые правилами выбираются первыми; если остались
+// только кандидаты на пределе лимита, возвращается ErrAllReviewersAtCapacity.
+func (s *Service) selectReplacement(pr PullRequest, team string, stats []ReviewerStats, rules authorRules) (Selection, error) {
+	current := map[string]struct{}{}
+	for _, id := range pr.ReviewerIDs {
+		current[id] = struct{}{}
+	}
+
+	candidates := make([]ReviewerStats, 0, len(stats))
+	for _, st := range stats {
+		if st.UserID == pr.AuthorID {
+			continue
+		}
+		if _, exists := current[st.UserID]; exists {
+			continue
+		}
+		if rules.isExcluded(st.UserID) {
+			continue
+		}
+		candidates = append(candidates, st)
+	}
+
+	if len(candidates) == 0 {
+		s.log.Warn("no candidate to reassign", "pr_id", pr.ID, "team", team)
+		return Selection{}, ErrNoCandidate
+	}
+	candidates = underCapacity(candidates)
+	if len(candidates) == 0 {
+		s.log.Warn("all candidates at capacity", "pr_id", pr.ID, "team", team)
+		return Selection{}, ErrAllReviewersAtCapacity
+	}
+
+	prefix := ""
+	if preferred, _ := rules.split(candidates); len(preferred) > 0 {
+		candidates, prefix = preferred, "preferred by rule; "
+	}
+
+	selector := s.selectors.For(team)
+	selection := selector.Select(SelectionInput{
+		PR:         pr,
+		Author:     User{ID: pr.AuthorID},
+		Team:       team,
+		Candidates: candidates,
+		Count:      1,
+	})
+	selection.Reason = prefix + selection.Reason
+	if len(selection.ReviewerIDs) == 0 {
+		s.log.Warn("selector returned no candidate", "pr_id", pr.ID, "selector", selector.Name())
+		return Selection{}, ErrNoCandidate
+	}
+	s.log.Info("replacement selected", "pr_id", pr.ID, "selector", selector.Name(), "reason", selection.Reason)
+
+	return selection, nil
+}
+
+// replacementPlan — команды, в которых по порядку ищется замена, и правила
+// подбора автора PR.
+type replacementPlan struct {
+	teams    []string
+	fallback map[string]struct{}
+	rules    authorRules
+}
+
+// planReplacement собирает план замены для PR автора authorID: сначала
+// команда team, затем команда автора home и её резервные команды, без
+// повторов.
+func (s *Service) planReplacement(ctx context.Context, authorID, team, home string) (replacementPlan, error) {
+	rules, err := s.rulesFor(ctx, User{ID: authorID, Team: home})
+	if err != nil {
+		return replacementPlan{}, err
+	}
+
+	plan, err := s.planTeams(ctx, team, home)
+	if err != nil {
+		return replacementPlan{}, err
+	}
+	plan.rules = rules
+	return plan, nil
+}
+
+// planTeams заполняет команды плана замены без правил подбора: они зависят
+// от автора, а команды — только от team и home.
+func (s *Service) planTeams(ctx context.Context, team, home string) (replacementPlan, error) {
+	plan := replacementPlan{teams: []string{team}, fallback: map[string]struct{}{}}
+	if home != "" {
+		if home != team {
+			plan.teams = append(plan.teams, home)
+		}
+		fallbacks, err := s.teamRepo.ListFallbacks(ctx, home)
+		if err != nil {
+			s.log.Error("failed to list fallback teams", "error", err, "team", home)
+			return replacementPlan{}, err
+		}
+		for _, fb := range fallbacks {
+			plan.fallback[fb] = struct{}{}
+			if fb != team {
+				plan.teams = append(plan.teams, fb)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// replaceFrom обходит команды плана и возвращает первый выбор и команду, из
+// которой он сделан. ErrAllReviewersAtCapacity возвращается, если хотя бы в
+// одной команде кандидаты были, но все набрали лимит.
+func (s *Service) replaceFrom(ctx context.Context, pr PullRequest, plan replacementPlan, load statsLoader) (Selection, string, error) {
+	lastErr := ErrNoCandidate
+	for _, t := range plan.teams {
+		stats, err := load(ctx, t)
+		if err != nil {
+			return Selection{}, "", err
+		}
+		selection, err := s.selectReplacement(pr, t, stats, plan.rules)
+		if err == ErrNoCandidate || err == ErrAllReviewersAtCapacity {
+			if err == ErrAllReviewersAtCapacity {
+				lastErr = err
+			}
+			continue
+		}
+		if err != nil {
+			return Selection{}, "", err
+		}
+		if _, ok := plan.fallback[t]; ok {
+			selection.Reason = "fallback team " + t + "; " + selection.Reason
+		}
+		return selection, t, nil
+	}
+	return Selection{}, "", lastErr
+}
+
+// pickReplacement ищет замену сначала в команде team, затем в команде автора
+// home и её резервных командах, соблюдая правила подбора команды home.
+// Возвращает выбор и команду, из которой он сделан.
+func (s *Service) pickReplacement(ctx context.Context, pr PullRequest, team, home string) (Selection, string, error) {
+	plan, err := s.planReplacement(ctx, pr.AuthorID, team, home)
+	if err != nil {
+		return Selection{}, "", err
+	}
+	return s.replaceFrom(ctx, pr, plan, s.availableReviewerStats)
+}
+
+// replaceReviewer меняет oldID на newID из команды newTeam в PR и пишет
+// событие REVIEWER_REASSIGNED.
+func (s *Service) replaceReviewer(ctx context.Context, pr PullRequest, oldID, newID, newTeam, details string) (PullRequest, error) {
+	pr.ReviewerIDs = replaceID(pr.ReviewerIDs, oldID, newID)
+	teams := make(map[string]string, len(pr.ReviewerTeams)+1)
+	for id, t := range pr.ReviewerTeams {
+		if id != oldID {
+			teams[id] = t
+		}
+	}
+	teams[newID] = newTeam
+	pr.ReviewerTeams = teams
+
+	ev := newEvent(ctx, pr.ID, EventReviewerReassigned)
+	ev.OldReviewerID = oldID
+	ev.NewReviewerID = newID
+	ev.Details = details
+
+	updated, err := s.prRepo.Update(ctx, pr, ev)
+	if err != nil {
+		s.log.Error("failed to update PR with new reviewer", "error", err, "pr_id", pr.ID)
+		return PullRequest{}, err
+	}
+	return updated, nil
+}
+
+// replaceID возвращает копию ids, в которой oldID заменён на newID.
+func replaceID(ids []string, oldID, newID string) []string {
+	out := append([]string(nil), ids...)
+	for i, id := range out {
+		if id == oldID {
+			out[i] = newID
+			break
+		}
+	}
+	return out
+}
+
+// redistributeReviews передаёт открытые ревью пользователя кандидатам из
+// команды автора каждого PR или её резервных команд. Вызывается после того, как пользователь уже
+// выведен из пула (сменил команду, деактивирован и т.п.); cause попадает в
+// историю PR. PR без подходящего кандидата остаются за пользователем и
+// перечисляются в отчёте.
+func (s *Service) redistributeReviews(ctx context.Context, reviewerID, cause string) (ReassignmentReport, error) {
+	var report ReassignmentReport
+
+	prs, err := s.prRepo.ListAssignedTo(ctx, reviewerID)
+	if err != nil {
+		s.log.Error("failed to list assigned PRs", "error", err, "user_id", reviewerID)
+		return report, err
+	}
+
+	for _, pr := range prs {
+		if pr.Status != StatusOpen {
+			continue
+		}
+
+		author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			s.log.Error("failed to get PR author", "error", err, "pr_id", pr.ID, "author_id", pr.AuthorID)
+			return report, err
+		}
+
+		var (
+			selection Selection
+			team      string
+		)
+		if author.Team == "" {
+			err = ErrNoCandidate
+		} else {
+			selection, team, err = s.pickReplacement(ctx, pr, author.Team, author.Team)
+		}
+		if err == ErrNoCandidate || err == ErrAllReviewersAtCapacity {
+			report.Failed = append(report.Failed, ReassignmentFailure{
+				PRID:       pr.ID,
+				ReviewerID: reviewerID,
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+
+		newID := selection.ReviewerIDs[0]
+		if _, err := s.replaceReviewer(ctx, pr, reviewerID, newID, team, cause+"; "+selection.Reason); err != nil {
+			return report, err
+		}
+		report.Reassigned = append(report.Reassigned, Reassignment{
+			PRID:          pr.ID,
+			OldReviewerID: reviewerID,
+			NewReviewerID: newID,
+		})
+	}
+
+	s.log.Info("reviews redistributed", "user_id", reviewerID,
+		"reassigned", len(report.Reassigned), "failed", len(report.Failed))
+	return report, nil
+}
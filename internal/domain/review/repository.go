@@ -2,25 +2,86 @@ package review
 
 import (
 	"context"
+	"time"
 )
 
+// PRRepository сохраняет переданные события PR в той же транзакции,
+// что и изменение самого PR. Update выполняет compare-and-swap по
+// PullRequest.Version и возвращает ErrConflict, если PR успел измениться;
+// AddReview так же проверяет и увеличивает версию PR version. PullRequest.ReviewerTeams хранится
+// вместе с назначениями; при удалении команды её записи пропадают.
+// Repository, ChangedFiles и Labels сохраняются в Create и в Update не
+// меняются.
 type PRRepository interface {
-	Create(ctx context.Context, pr PullRequest) (PullRequest, error)
-	Update(ctx context.Context, pr PullRequest) (PullRequest, error)
+	Create(ctx context.Context, pr PullRequest, events ...PREvent) (PullRequest, error)
+	Update(ctx context.Context, pr PullRequest, events ...PREvent) (PullRequest, error)
 	GetByID(ctx context.Context, id string) (PullRequest, error)
 	ListAssignedTo(ctx context.Context, userID string) ([]PullRequest, error)
+	AddReview(ctx context.Context, rv Review, version int64, events ...PREvent) (Review, error)
+	ListEvents(ctx context.Context, prID string) ([]PREvent, error)
+	// ListReviewerStats возвращает всех активных участников команды с числом
+	// назначенных им OPEN PR (включая нулевую нагрузку), действующим лимитом,
+	// тегами и уровнем, по возрастанию нагрузки.
 	ListReviewerStats(ctx context.Context, teamName string) ([]ReviewerStats, error)
+	// ListOpenByReviewers возвращает OPEN PR, где ревьювер — любой из
+	// reviewerIDs, без истории ревью, файлов и меток (Reviews, ChangedFiles и
+	// Labels не заполняются).
+	ListOpenByReviewers(ctx context.Context, reviewerIDs []string) ([]PullRequest, error)
+	// ReplaceReviewers пакетно применяет замены ревьюверов. Версия каждого
+	// затронутого PR проверяется по ReviewerSwap.Version и увеличивается на
+	// единицу; при несовпадении возвращается ErrConflict и ничего не меняется.
+	ReplaceReviewers(ctx context.Context, swaps []ReviewerSwap, events ...PREvent) error
+	// SetOwnershipRules заменяет правила владения кодом репозитория, сохраняя
+	// их порядок; ErrNotFound, если владельца нет среди пользователей.
+	SetOwnershipRules(ctx context.Context, repository string, rules []OwnershipRule) error
+	ListOwnershipRules(ctx context.Context, repository string) ([]OwnershipRule, error)
 }
 
+// UserRepository хранит пользователей; User.Team == "" означает, что
+// пользователь не состоит ни в одной команде. AddAbsence возвращает
+// ErrNotFound для неизвестного пользователя; ListTeamAbsences отдаёт
+// отсутствия участников команды, не закончившиеся к моменту from, по
+// возрастанию StartsAt. Теги читаются вместе с пользователем, а меняются
+// только через SetTags (ErrNotFound для неизвестного пользователя).
+// ListByIDs читает пользователей одним запросом по возрастанию ID и
+// пропускает неизвестные ID.
 type UserRepository interface {
 	GetByID(ctx context.Context, id string) (User, error)
+	ListByIDs(ctx context.Context, ids []string) ([]User, error)
 	Create(ctx context.Context, u User) (User, error)
 	Update(ctx context.Context, u User) (User, error)
 	ListByTeam(ctx context.Context, teamName string) ([]User, error)
 	ListActiveByTeam(ctx context.Context, teamName string) ([]User, error)
+	SetActive(ctx context.Context, userIDs []string, active bool) error
+	AddAbsence(ctx context.Context, a Absence) (Absence, error)
+	ListTeamAbsences(ctx context.Context, teamName string, from time.Time) ([]Absence, error)
+	SetTags(ctx context.Context, userID string, tags []string) error
 }
 
+// TeamRepository: Update меняет настройки команды по имени, Rename переносит
+// участников на новое имя команды, Delete удаляет только команду без участников.
+// SetFallbacks заменяет упорядоченный список резервных команд (ErrNotFound,
+// если какой-то команды нет); переименование и удаление команды отражаются
+// в списках резерва автоматически. SetRoleQuotas так же заменяет квоты ролей
+// команды с сохранением порядка, SetReviewerRules — правила подбора
+// ревьюверов (ErrNotFound, если автора или ревьювера нет).
 type TeamRepository interface {
 	GetByName(ctx context.Context, name string) (Team, error)
 	Create(ctx context.Context, t Team) (Team, error)
+	Update(ctx context.Context, t Team) (Team, error)
+	Rename(ctx context.Context, oldName, newName string) (Team, error)
+	Delete(ctx context.Context, name string) error
+	SetFallbacks(ctx context.Context, teamName string, fallbacks []string) error
+	ListFallbacks(ctx context.Context, teamName string) ([]string, error)
+	SetRoleQuotas(ctx context.Context, teamName string, quotas []RoleQuota) error
+	ListRoleQuotas(ctx context.Context, teamName string) ([]RoleQuota, error)
+	SetReviewerRules(ctx context.Context, teamName string, rules []ReviewerRule) error
+	ListReviewerRules(ctx context.Context, teamName string) ([]ReviewerRule, error)
+}
+
+// TxManager выполняет fn в одной транзакции: репозитории, вызванные с
+// переданным в fn контекстом, либо применяют все изменения, либо ни одного.
+// Вложенный вызов WithinTx использует уже открытую транзакцию.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
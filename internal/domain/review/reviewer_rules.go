@@ -0,0 +1,219 @@
+package review
+
+import "context"
+
+// ReviewerRuleType — вид правила подбора ревьюверов.
+type ReviewerRuleType string
+
+const (
+	// RuleExclude запрещает назначать ReviewerID на PR автора AuthorID.
+	RuleExclude ReviewerRuleType = "EXCLUDE"
+	// RulePrefer выбирает ReviewerID первым, когда он доступен и подходит
+	// на текущем этапе подбора.
+	RulePrefer ReviewerRuleType = "PREFER"
+)
+
+// ReviewerRule — правило команды для PR автора AuthorID. Правила команды
+// действуют на PR её участников при создании, переводе из черновика,
+// переназначении и перераспределении ревью.
+type ReviewerRule struct {
+	Type       ReviewerRuleType
+	AuthorID   string
+	ReviewerID string
+}
+
+// Validate проверяет вид правила и пару автор/ревьювер.
+func (r ReviewerRule) Validate() error {
+	if r.Type != RuleExclude && r.Type != RulePrefer {
+		return ErrInvalidReviewerRules
+	}
+	if r.AuthorID == "" || r.ReviewerID == "" || r.AuthorID == r.ReviewerID {
+		return ErrInvalidReviewerRules
+	}
+	return nil
+}
+
+// RuleDecision — итог проверки кандидата правилами команды автора.
+type RuleDecision string
+
+const (
+	DecisionEligible  RuleDecision = "ELIGIBLE"
+	DecisionPreferred RuleDecision = "PREFERRED"
+	DecisionExcluded  RuleDecision = "EXCLUDED"
+)
+
+// RuleCheck — кандидат из команды автора или её резерва и правило, которое
+// к нему применяется (nil, если ни одно).
+type RuleCheck struct {
+	UserID   string
+	TeamName string
+	Decision RuleDecision
+	Rule     *ReviewerRule
+}
+
+// RulesDryRun — объяснение правил подбора для PR автора AuthorID без
+// назначения ревьюверов.
+type RulesDryRun struct {
+	AuthorID   string
+	TeamName   string
+	Candidates []RuleCheck
+}
+
+// authorRules — правила команды автора одного PR по ревьюверам.
+type authorRules struct {
+	excluded  map[string]ReviewerRule
+	preferred map[string]ReviewerRule
+}
+
+// rulesFor загружает правила команды автора, относящиеся к его PR.
+func (s *Service) rulesFor(ctx context.Context, author User) (authorRules, error) {
+	rules := authorRules{excluded: map[string]ReviewerRule{}, preferred: map[string]ReviewerRule{}}
+	if author.Team == "" {
+		return rules, nil
+	}
+
+	all, err := s.teamRepo.ListReviewerRules(ctx, author.Team)
+	if err != nil {
+		s.log.Error("failed to list reviewer rules", "error", err, "team", author.Team)
+		return authorRules{}, err
+	}
+	rules = newAuthorRules(author.ID, all)
+	if len(rules.excluded)+len(rules.preferred) > 0 {
+		s.log.Info("reviewer rules apply", "author_id", author.ID, "team", author.Team,
+			"excluded", len(rules.excluded), "preferred", len(rules.preferred))
+	}
+	return rules, nil
+}
+
+// newAuthorRules отбирает из правил команды те, что относятся к автору authorID.
+func newAuthorRules(authorID string, all []ReviewerRule) authorRules {
+	rules := authorRules{excluded: map[string]ReviewerRule{}, preferred: map[string]ReviewerRule{}}
+	for _, r := range all {
+		if r.AuthorID != authorID {
+			continue
+		}
+		if r.Type == RuleExclude {
+			rules.excluded[r.ReviewerID] = r
+		} else {
+			rules.preferred[r.ReviewerID] = r
+		}
+	}
+	return rules
+}
+
+func (r authorRules) isExcluded(userID string) bool {
+	_, ok := r.excluded[userID]
+	return ok
+}
+
+// split делит кандидатов на предпочтённых автором и остальных.
+func (r authorRules) split(candidates []ReviewerStats) (preferred, others []ReviewerStats) {
+	for _, st := range candidates {
+		if _, ok := r.preferred[st.UserID]; ok {
+			preferred = append(preferred, st)
+		} else {
+			others = append(others, st)
+		}
+	}
+	return preferred, others
+}
+
+// check возвращает решение правил по одному кандидату.
+func (r authorRules) check(userID string) (RuleDecision, *ReviewerRule) {
+	if rule, ok := r.excluded[userID]; ok {
+		return DecisionExcluded, &rule
+	}
+	if rule, ok := r.preferred[userID]; ok {
+		return DecisionPreferred, &rule
+	}
+	return DecisionEligible, nil
+}
+
+// SetReviewerRules заменяет правила подбора ревьюверов команды целиком.
+// Одна пара автор/ревьювер может встречаться только в одном правиле;
+// неизвестные пользователи дают ErrNotFound.
+func (s *Service) SetReviewerRules(ctx context.Context, teamName string, rules []ReviewerRule) ([]ReviewerRule, error) {
+	return inTx(ctx, s, func(ctx context.Context) ([]ReviewerRule, error) {
+		s.log.Info("SetReviewerRules called", "team", teamName, "rules_count", len(rules))
+
+		type pair struct{ author, reviewer string }
+		seen := make(map[pair]struct{}, len(rules))
+		for _, r := range rules {
+			if err := r.Validate(); err != nil {
+				s.log.Warn("invalid reviewer rule", "team", teamName, "rule", r)
+				return nil, err
+			}
+			key := pair{r.AuthorID, r.ReviewerID}
+			if _, dup := seen[key]; dup {
+				s.log.Warn("duplicate reviewer rule", "team", teamName, "rule", r)
+				return nil, ErrInvalidReviewerRules
+			}
+			seen[key] = struct{}{}
+		}
+
+		if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+			s.log.Warn("failed to get team", "team", teamName, "error", err)
+			return nil, err
+		}
+		if err := s.teamRepo.SetReviewerRules(ctx, teamName, rules); err != nil {
+			s.log.Error("failed to set reviewer rules", "team", teamName, "error", err)
+			return nil, err
+		}
+		return s.teamRepo.ListReviewerRules(ctx, teamName)
+	})
+}
+
+func (s *Service) ListReviewerRules(ctx context.Context, teamName string) ([]ReviewerRule, error) {
+	return s.teamRepo.ListReviewerRules(ctx, teamName)
+}
+
+// DryRunReviewerRules показывает, как правила команды автора разделят
+// активных участников его команды и резервных команд: кто исключён, кто
+// предпочтён и каким правилом. Нагрузка и отсутствия здесь не учитываются.
+func (s *Service) DryRunReviewerRules(ctx context.Context, authorID string) (RulesDryRun, error) {
+	s.log.Info("DryRunReviewerRules called", "author_id", authorID)
+
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		s.log.Warn("failed to get author", "author_id", authorID, "error", err)
+		return RulesDryRun{}, err
+	}
+	if author.Team == "" {
+		s.log.Warn("author has no team", "author_id", authorID)
+		return RulesDryRun{}, ErrNotInTeam
+	}
+
+	rules, err := s.rulesFor(ctx, author)
+	if err != nil {
+		return RulesDryRun{}, err
+	}
+	fallbacks, err := s.teamRepo.ListFallbacks(ctx, author.Team)
+	if err != nil {
+		s.log.Error("failed to list fallback teams", "error", err, "team", author.Team)
+		return RulesDryRun{}, err
+	}
+
+	res := RulesDryRun{AuthorID: author.ID, TeamName: author.Team}
+	seen := map[string]struct{}{author.ID: {}}
+	for _, team := range append([]string{author.Team}, fallbacks...) {
+		users, err := s.userRepo.ListActiveByTeam(ctx, team)
+		if err != nil {
+			s.log.Error("failed to list active users", "error", err, "team", team)
+			return RulesDryRun{}, err
+		}
+		for _, u := range users {
+			if _, ok := seen[u.ID]; ok {
+				continue
+			}
+			seen[u.ID] = struct{}{}
+			decision, rule := rules.check(u.ID)
+			res.Candidates = append(res.Candidates, RuleCheck{
+				UserID:   u.ID,
+				TeamName: team,
+				Decision: decision,
+				Rule:     rule,
+			})
+		}
+	}
+	return res, nil
+}
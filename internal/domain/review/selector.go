@@ -0,0 +1,166 @@
+package review
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+const (
+	SelectorRandom      = "random"
+	SelectorLeastLoaded = "least_loaded"
+	SelectorRoundRobin  = "round_robin"
+)
+
+type SelectionInput struct {
+	PR         PullRequest
+	Author     User
+	Team       string
+	Candidates []ReviewerStats
+	Count      int
+}
+
+type Selection struct {
+	ReviewerIDs []string
+	Reason      string
+}
+
+// ReviewerSelector выбирает ревьюверов из уже отфильтрованного пула кандидатов.
+// Кандидаты не содержат автора и текущих ревьюверов PR.
+type ReviewerSelector interface {
+	Name() string
+	Select(in SelectionInput) Selection
+}
+
+// Selectors хранит стратегию по умолчанию и переопределения для отдельных команд.
+type Selectors struct {
+	Default ReviewerSelector
+	Teams   map[string]ReviewerSelector
+}
+
+func (s Selectors) For(team string) ReviewerSelector {
+	if sel, ok := s.Teams[team]; ok && sel != nil {
+		return sel
+	}
+	return s.Default
+}
+
+// NewSelector создаёт стратегию по имени из конфига. Каждая стратегия получает
+// собственный источник случайности, производный от randSrc.
+func NewSelector(name string, randSrc *rand.Rand) (ReviewerSelector, error) {
+	switch name {
+	case "", SelectorRandom:
+		return NewRandomSelector(rand.New(rand.NewSource(randSrc.Int63()))), nil
+	case SelectorLeastLoaded:
+		return NewLeastLoadedSelector(rand.New(rand.NewSource(randSrc.Int63()))), nil
+	case SelectorRoundRobin:
+		return NewRoundRobinSelector(), nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer selector %q", name)
+	}
+}
+
+// lockedRand делает *rand.Rand безопасным для конкурентного использования.
+type lockedRand struct {
+	mu  sync.Mutex
+	src *rand.Rand
+}
+
+func (r *lockedRand) shuffle(c []ReviewerStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.src.Shuffle(len(c), func(i, j int) {
+		c[i], c[j] = c[j], c[i]
+	})
+}
+
+type RandomSelector struct {
+	rnd *lockedRand
+}
+
+func NewRandomSelector(randSrc *rand.Rand) *RandomSelector {
+	return &RandomSelector{rnd: &lockedRand{src: randSrc}}
+}
+
+func (s *RandomSelector) Name() string { return SelectorRandom }
+
+func (s *RandomSelector) Select(in SelectionInput) Selection {
+	candidates := append([]ReviewerStats(nil), in.Candidates...)
+	s.rnd.shuffle(candidates)
+
+	return Selection{
+		ReviewerIDs: firstIDs(candidates, in.Count),
+		Reason:      fmt.Sprintf("random pick among %d candidates", len(candidates)),
+	}
+}
+
+// LeastLoadedSelector выбирает кандидатов с наименьшим числом открытых ревью,
+// при равной нагрузке порядок случайный.
+type LeastLoadedSelector struct {
+	rnd *lockedRand
+}
+
+func NewLeastLoadedSelector(randSrc *rand.Rand) *LeastLoadedSelector {
+	return &LeastLoadedSelector{rnd: &lockedRand{src: randSrc}}
+}
+
+func (s *LeastLoadedSelector) Name() string { return SelectorLeastLoaded }
+
+func (s *LeastLoadedSelector) Select(in SelectionInput) Selection {
+	candidates := append([]ReviewerStats(nil), in.Candidates...)
+	s.rnd.shuffle(candidates)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].AssignedOpenPRs < candidates[j].AssignedOpenPRs
+	})
+
+	picked := firstIDs(candidates, in.Count)
+	reason := "no candidates"
+	if len(picked) > 0 {
+		reason = fmt.Sprintf("least loaded among %d candidates (min open reviews %d)",
+			len(candidates), candidates[0].AssignedOpenPRs)
+	}
+	return Selection{ReviewerIDs: picked, Reason: reason}
+}
+
+// RoundRobinSelector назначает ревью по кругу: первыми выбираются те, кому
+// ревью назначалось давнее всех, никогда не назначавшиеся — в порядке
+// user_id. Очередь выводится из истории PR (ReviewerStats.LastAssigned), а не
+// из памяти процесса, поэтому её не сбивают откат транзакции, переименование
+// команды, перезапуск и несколько экземпляров сервиса.
+type RoundRobinSelector struct{}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{}
+}
+
+func (s *RoundRobinSelector) Name() string { return SelectorRoundRobin }
+
+func (s *RoundRobinSelector) Select(in SelectionInput) Selection {
+	candidates := append([]ReviewerStats(nil), in.Candidates...)
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].LastAssigned != candidates[j].LastAssigned {
+			return candidates[i].LastAssigned < candidates[j].LastAssigned
+		}
+		return candidates[i].UserID < candidates[j].UserID
+	})
+
+	return Selection{
+		ReviewerIDs: firstIDs(candidates, in.Count),
+		Reason:      fmt.Sprintf("round robin among %d candidates (least recently assigned first)", len(candidates)),
+	}
+}
+
+func firstIDs(candidates []ReviewerStats, n int) []string {
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	if n < 0 {
+		n = 0
+	}
+	ids := make([]string, 0, n)
+	for _, c := range candidates[:n] {
+		ids = append(ids, c.UserID)
+	}
+	return ids
+}
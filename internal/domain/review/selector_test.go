@@ -0,0 +1,164 @@
+package review
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func stats(loads map[string]int) []ReviewerStats {
+	out := make([]ReviewerStats, 0, len(loads))
+	for id, n := range loads {
+		out = append(out, ReviewerStats{UserID: id, AssignedOpenPRs: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UserID < out[j].UserID })
+	return out
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	tests := []struct {
+		name  string
+		loads map[string]int
+		count int
+		// want — множества, из которых по порядку должен быть выбран
+		// каждый следующий ревьювер.
+		want [][]string
+	}{
+		{"single least loaded", map[string]int{"a": 2, "b": 0, "c": 1}, 1, [][]string{{"b"}}},
+		{"two least loaded", map[string]int{"a": 2, "b": 0, "c": 1}, 2, [][]string{{"b"}, {"c"}}},
+		{"tie is broken among tied only", map[string]int{"a": 1, "b": 1, "c": 3}, 1, [][]string{{"a", "b"}}},
+		{"tie then next load", map[string]int{"a": 0, "b": 0, "c": 2}, 3, [][]string{{"a", "b"}, {"a", "b"}, {"c"}}},
+		{"count above candidates", map[string]int{"a": 0}, 2, [][]string{{"a"}}},
+		{"no candidates", map[string]int{}, 1, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				sel := NewLeastLoadedSelector(rand.New(rand.NewSource(seed)))
+				got := sel.Select(SelectionInput{Candidates: stats(tc.loads), Count: tc.count}).ReviewerIDs
+				if len(got) != len(tc.want) {
+					t.Fatalf("seed %d: picked %v, want %d reviewers", seed, got, len(tc.want))
+				}
+				for i, id := range got {
+					allowed := tc.want[i]
+					if !slices.Contains(allowed, id) {
+						t.Fatalf("seed %d: pick %d = %q, want one of %v", seed, i, id, allowed)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLeastLoadedSelectorRandomizesTies(t *testing.T) {
+	seen := map[string]bool{}
+	for seed := int64(0); seed < 50; seed++ {
+		sel := NewLeastLoadedSelector(rand.New(rand.NewSource(seed)))
+		got := sel.Select(SelectionInput{Candidates: stats(map[string]int{"a": 0, "b": 0, "c": 5}), Count: 1})
+		seen[got.ReviewerIDs[0]] = true
+	}
+	if !seen["a"] || !seen["b"] || seen["c"] {
+		t.Fatalf("tie picks = %v, want both a and b and never c", seen)
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	tests := []struct {
+		name  string
+		cands []ReviewerStats
+		count int
+		want  []string
+	}{
+		{
+			name:  "never assigned go first in user_id order",
+			cands: []ReviewerStats{{UserID: "c"}, {UserID: "a", LastAssigned: 5}, {UserID: "b"}},
+			count: 2,
+			want:  []string{"b", "c"},
+		},
+		{
+			name:  "least recently assigned first",
+			cands: []ReviewerStats{{UserID: "a", LastAssigned: 9}, {UserID: "b", LastAssigned: 3}, {UserID: "c", LastAssigned: 7}},
+			count: 2,
+			want:  []string{"b", "c"},
+		},
+		{
+			name:  "load is ignored",
+			cands: []ReviewerStats{{UserID: "a", LastAssigned: 1, AssignedOpenPRs: 9}, {UserID: "b", LastAssigned: 2}},
+			count: 1,
+			want:  []string{"a"},
+		},
+		{
+			name:  "no candidates",
+			count: 1,
+			want:  []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewRoundRobinSelector().Select(SelectionInput{Candidates: tc.cands, Count: tc.count}).ReviewerIDs
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("picked %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRoundRobinSelectorRotation(t *testing.T) {
+	team := []ReviewerStats{{UserID: "b"}, {UserID: "a"}, {UserID: "c"}}
+	sel := NewRoundRobinSelector()
+
+	var (
+		got []string
+		seq int64
+	)
+	for range 7 {
+		picked := sel.Select(SelectionInput{Candidates: team, Count: 1}).ReviewerIDs[0]
+		got = append(got, picked)
+		// Назначение попадает в историю PR — так очередь и сдвигается.
+		seq++
+		for i := range team {
+			if team[i].UserID == picked {
+				team[i].LastAssigned = seq
+			}
+		}
+	}
+
+	want := []string{"a", "b", "c", "a", "b", "c", "a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("rotation = %v, want %v", got, want)
+	}
+}
+
+// Выбор без записи в историю (предпросмотр, откат транзакции) очередь не
+// сдвигает: у стратегии нет собственного состояния.
+func TestRoundRobinSelectorWithoutAssignmentDoesNotAdvance(t *testing.T) {
+	team := []ReviewerStats{{UserID: "a", LastAssigned: 4}, {UserID: "b", LastAssigned: 2}, {UserID: "c", LastAssigned: 3}}
+	sel := NewRoundRobinSelector()
+
+	first := sel.Select(SelectionInput{Team: "backend", Candidates: team, Count: 1}).ReviewerIDs
+	for range 3 {
+		again := sel.Select(SelectionInput{Team: "backend", Candidates: team, Count: 1}).ReviewerIDs
+		if !reflect.DeepEqual(again, first) {
+			t.Fatalf("repeated pick = %v, want %v", again, first)
+		}
+	}
+	// Переименованная команда продолжает ту же очередь.
+	renamed := sel.Select(SelectionInput{Team: "platform", Candidates: team, Count: 1}).ReviewerIDs
+	if !reflect.DeepEqual(renamed, first) {
+		t.Fatalf("pick after rename = %v, want %v", renamed, first)
+	}
+}
+
+func TestRandomSelector(t *testing.T) {
+	cands := stats(map[string]int{"a": 0, "b": 3, "c": 1})
+	for seed := int64(0); seed < 20; seed++ {
+		got := NewRandomSelector(rand.New(rand.NewSource(seed))).Select(SelectionInput{Candidates: cands, Count: 2}).ReviewerIDs
+		if len(got) != 2 || got[0] == got[1] {
+			t.Fatalf("seed %d: picked %v, want two distinct reviewers", seed, got)
+		}
+	}
+}
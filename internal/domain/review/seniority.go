@@ -0,0 +1,106 @@
+package review
+
+import "context"
+
+// Seniority — уровень пользователя, по которому команда распределяет места
+// ревьюверов через квоты ролей.
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "JUNIOR"
+	SeniorityMiddle Seniority = "MIDDLE"
+	SenioritySenior Seniority = "SENIOR"
+)
+
+// validateSeniority проверяет уровень пользователя; "" означает «не задан».
+func validateSeniority(s Seniority) error {
+	switch s {
+	case "", SeniorityJunior, SeniorityMiddle, SenioritySenior:
+		return nil
+	}
+	return ErrInvalidSeniority
+}
+
+// RoleQuota — сколько ревьюверов уровня Seniority команда назначает на PR.
+// Места сверх суммы квот заполняются ревьюверами любого уровня, поэтому
+// «1 senior + 1 any» задаётся одной квотой SENIOR: 1.
+type RoleQuota struct {
+	Seniority Seniority
+	Count     int
+}
+
+// validateRoleQuotas проверяет квоты команды: уровень задан и не повторяется,
+// Count > 0, а сумма квот не больше числа ревьюверов на PR.
+func validateRoleQuotas(team Team, quotas []RoleQuota) error {
+	seen := make(map[Seniority]struct{}, len(quotas))
+	total := 0
+	for _, q := range quotas {
+		if q.Seniority == "" || validateSeniority(q.Seniority) != nil || q.Count < 1 {
+			return ErrInvalidRoleQuotas
+		}
+		if _, dup := seen[q.Seniority]; dup {
+			return ErrInvalidRoleQuotas
+		}
+		seen[q.Seniority] = struct{}{}
+		total += q.Count
+	}
+	if total > team.MaxReviewers {
+		return ErrInvalidRoleQuotas
+	}
+	return nil
+}
+
+// SetUserSeniority задаёт уровень пользователя; "" снимает его. Уже
+// назначенные ревью не меняются.
+func (s *Service) SetUserSeniority(ctx context.Context, userID string, seniority Seniority) (User, error) {
+	return inTx(ctx, s, func(ctx context.Context) (User, error) {
+		s.log.Info("SetUserSeniority called", "user_id", userID, "seniority", seniority)
+
+		if err := validateSeniority(seniority); err != nil {
+			s.log.Warn("invalid seniority", "user_id", userID, "seniority", seniority)
+			return User{}, err
+		}
+
+		u, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			s.log.Warn("failed to get user", "user_id", userID, "error", err)
+			return User{}, err
+		}
+		u.Seniority = seniority
+
+		updated, err := s.userRepo.Update(ctx, u)
+		if err != nil {
+			s.log.Error("failed to update user seniority", "user_id", userID, "error", err)
+			return User{}, err
+		}
+		return updated, nil
+	})
+}
+
+// SetTeamRoleQuotas заменяет квоты ролей команды; пустой список возвращает
+// назначение без учёта уровня.
+func (s *Service) SetTeamRoleQuotas(ctx context.Context, teamName string, quotas []RoleQuota) ([]RoleQuota, error) {
+	return inTx(ctx, s, func(ctx context.Context) ([]RoleQuota, error) {
+		s.log.Info("SetTeamRoleQuotas called", "team", teamName, "quotas", quotas)
+
+		team, err := s.teamRepo.GetByName(ctx, teamName)
+		if err != nil {
+			s.log.Warn("failed to get team", "team", teamName, "error", err)
+			return nil, err
+		}
+		if err := validateRoleQuotas(team, quotas); err != nil {
+			s.log.Warn("invalid role quotas", "team", teamName, "quotas", quotas, "max_reviewers", team.MaxReviewers)
+			return nil, err
+		}
+
+		if err := s.teamRepo.SetRoleQuotas(ctx, teamName, quotas); err != nil {
+			s.log.Error("failed to set role quotas", "team", teamName, "error", err)
+			return nil, err
+		}
+		return s.teamRepo.ListRoleQuotas(ctx, teamName)
+	})
+}
+
+func (s *Service) ListTeamRoleQuotas(ctx context.Context, teamName string) ([]RoleQuota, error) {
+	return s.teamRepo.ListRoleQuotas(ctx, teamName)
+}
@@ -0,0 +1,1207 @@
+package review_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/repository/memory"
+)
+
+// newService собирает сервис поверх хранилища в памяти.
+func newService(t *testing.T, selector review.ReviewerSelector) *review.Service {
+	t.Helper()
+	return newServiceWithPolicy(t, selector, review.MergePolicy{RequiredApprovals: 1})
+}
+
+func newServiceWithPolicy(t *testing.T, selector review.ReviewerSelector, policy review.MergePolicy) *review.Service {
+	t.Helper()
+
+	st := memory.NewStore()
+	return review.NewService(
+		memory.NewPRRepo(st), memory.NewUserRepo(st), memory.NewTeamRepo(st), memory.NewTxManager(st),
+		review.Selectors{Default: selector},
+		policy,
+		rand.New(rand.NewSource(1)),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+}
+
+func member(id string) review.User {
+	return review.User{ID: id, Name: "name-" + id, IsActive: true}
+}
+
+func mustCreateTeam(t *testing.T, s *review.Service, team review.Team, members ...review.User) {
+	t.Helper()
+	if _, err := s.CreateTeam(context.Background(), team, members); err != nil {
+		t.Fatalf("create team %q: %v", team.Name, err)
+	}
+}
+
+func mustCreatePR(t *testing.T, s *review.Service, id, author string) review.PullRequest {
+	t.Helper()
+	pr, err := s.CreatePR(context.Background(), review.PullRequest{ID: id, Title: "title-" + id, AuthorID: author}, review.CreatePROptions{})
+	if err != nil {
+		t.Fatalf("create pr %q: %v", id, err)
+	}
+	return pr
+}
+
+func wantReviewers(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s reviewers = %v, want %v", what, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s reviewers = %v, want %v", what, got, want)
+		}
+	}
+}
+
+// Очередь round_robin сдвигают только сохранённые назначения: предпросмотр
+// и откаченная транзакция её не трогают, переименование команды не сбрасывает.
+func TestRoundRobinQueueFollowsCommittedAssignments(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1},
+		member("a"), member("b"), member("c"), member("d"))
+
+	preview := func() []string {
+		t.Helper()
+		p, err := s.PreviewAssignment(ctx, review.PullRequest{AuthorID: "a"}, review.CreatePROptions{})
+		if err != nil {
+			t.Fatalf("preview: %v", err)
+		}
+		return p.ReviewerIDs
+	}
+
+	wantReviewers(t, "preview", preview(), "b")
+	wantReviewers(t, "second preview", preview(), "b")
+	wantReviewers(t, "pr1", mustCreatePR(t, s, "pr1", "a").ReviewerIDs, "b")
+
+	_, err := s.CreatePR(ctx, review.PullRequest{ID: "pr1", Title: "dup", AuthorID: "a"}, review.CreatePROptions{})
+	if !errors.Is(err, review.ErrPRExists) {
+		t.Fatalf("duplicate create error = %v, want %v", err, review.ErrPRExists)
+	}
+	wantReviewers(t, "preview after rollback", preview(), "c")
+	wantReviewers(t, "pr2", mustCreatePR(t, s, "pr2", "a").ReviewerIDs, "c")
+
+	if _, err := s.RenameTeam(ctx, "backend", "platform"); err != nil {
+		t.Fatalf("rename team: %v", err)
+	}
+	wantReviewers(t, "pr3", mustCreatePR(t, s, "pr3", "a").ReviewerIDs, "d")
+	wantReviewers(t, "pr4", mustCreatePR(t, s, "pr4", "a").ReviewerIDs, "b")
+}
+
+func TestCreatePRReviewersCountWithinTeamLimits(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 2, MaxReviewers: 3},
+		member("a"), member("b"), member("c"), member("d"), member("e"))
+
+	tests := []struct {
+		count   int
+		wantErr error
+		wantLen int
+	}{
+		{count: 0, wantLen: 3},
+		{count: 1, wantErr: review.ErrInvalidReviewersCount},
+		{count: 2, wantLen: 2},
+		{count: 3, wantLen: 3},
+		{count: 4, wantErr: review.ErrInvalidReviewersCount},
+	}
+	for i, tc := range tests {
+		pr, err := s.CreatePR(ctx, review.PullRequest{ID: fmt.Sprintf("pr%d", i), Title: "t", AuthorID: "a"},
+			review.CreatePROptions{ReviewersCount: tc.count})
+		if !errors.Is(err, tc.wantErr) {
+			t.Fatalf("reviewers_count %d: error = %v, want %v", tc.count, err, tc.wantErr)
+		}
+		if err == nil && len(pr.ReviewerIDs) != tc.wantLen {
+			t.Fatalf("reviewers_count %d: reviewers = %v, want %d", tc.count, pr.ReviewerIDs, tc.wantLen)
+		}
+	}
+}
+
+// Лимиты ревьюверов меняются после создания команды, но max_reviewers не
+// опускается ниже суммы квот ролей.
+func TestSetTeamReviewersLimits(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "solo", MinReviewers: 1, MaxReviewers: 2}, member("a"))
+
+	_, err := s.CreatePR(ctx, review.PullRequest{ID: "pr1", Title: "t", AuthorID: "a"}, review.CreatePROptions{})
+	if !errors.Is(err, review.ErrNotEnoughReviewers) {
+		t.Fatalf("create pr error = %v, want %v", err, review.ErrNotEnoughReviewers)
+	}
+
+	team, err := s.SetTeamReviewersLimits(ctx, "solo", 0, 2)
+	if err != nil {
+		t.Fatalf("set limits: %v", err)
+	}
+	if team.MinReviewers != 0 || team.MaxReviewers != 2 {
+		t.Fatalf("team limits = %d..%d, want 0..2", team.MinReviewers, team.MaxReviewers)
+	}
+	pr := mustCreatePR(t, s, "pr1", "a")
+	wantReviewers(t, "pr1", pr.ReviewerIDs)
+
+	if _, err := s.SetTeamReviewersLimits(ctx, "solo", 3, 2); !errors.Is(err, review.ErrInvalidReviewersCount) {
+		t.Fatalf("min > max error = %v, want %v", err, review.ErrInvalidReviewersCount)
+	}
+	if _, err := s.SetTeamRoleQuotas(ctx, "solo", []review.RoleQuota{{Seniority: review.SenioritySenior, Count: 2}}); err != nil {
+		t.Fatalf("set role quotas: %v", err)
+	}
+	if _, err := s.SetTeamReviewersLimits(ctx, "solo", 0, 1); !errors.Is(err, review.ErrInvalidRoleQuotas) {
+		t.Fatalf("max below quotas error = %v, want %v", err, review.ErrInvalidRoleQuotas)
+	}
+	if _, err := s.SetTeamReviewersLimits(ctx, "missing", 0, 1); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("missing team error = %v, want %v", err, review.ErrNotFound)
+	}
+}
+
+func TestMergePRPolicyAndForce(t *testing.T) {
+	s := newServiceWithPolicy(t, review.NewRoundRobinSelector(),
+		review.MergePolicy{RequiredApprovals: 1, RequireSeniorApproval: true, ForceAllowed: []string{"lead"}})
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("s"))
+	if _, err := s.SetUserSeniority(ctx, "s", review.SenioritySenior); err != nil {
+		t.Fatalf("set seniority: %v", err)
+	}
+
+	mustCreatePR(t, s, "pr1", "a")
+	_, err := s.MergePR(ctx, "pr1", false)
+	var blocked *review.MergeBlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, review.ErrMergeBlocked) {
+		t.Fatalf("merge error = %v, want MergeBlockedError", err)
+	}
+	want := []string{"requires 1 approvals, has 0", "requires approval from a SENIOR reviewer"}
+	if fmt.Sprint(blocked.Unmet) != fmt.Sprint(want) {
+		t.Fatalf("unmet = %q, want %q", blocked.Unmet, want)
+	}
+
+	for _, actor := range []string{"", "a"} {
+		_, err := s.MergePR(review.WithActor(ctx, actor), "pr1", true)
+		if !errors.Is(err, review.ErrForceNotAllowed) {
+			t.Fatalf("force merge by %q error = %v, want %v", actor, err, review.ErrForceNotAllowed)
+		}
+	}
+
+	forced, err := s.MergePR(review.WithActor(ctx, "lead"), "pr1", true)
+	if err != nil {
+		t.Fatalf("forced merge: %v", err)
+	}
+	if forced.Status != review.StatusMerged || !forced.MergeForced {
+		t.Fatalf("forced merge = status %s forced %v, want MERGED and forced", forced.Status, forced.MergeForced)
+	}
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.Type != review.EventMerged || last.ActorID != "lead" ||
+		last.Details != "forced by lead: requires 1 approvals, has 0; requires approval from a SENIOR reviewer" {
+		t.Fatalf("merge event = %+v, want forced MERGED", last)
+	}
+
+	mustCreatePR(t, s, "pr2", "a")
+	if _, err := s.SubmitReview(ctx, "pr2", "s", review.ReviewApproved, "lgtm"); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	merged, err := s.MergePR(ctx, "pr2", false)
+	if err != nil {
+		t.Fatalf("merge approved PR: %v", err)
+	}
+	if merged.MergeForced {
+		t.Fatalf("approved PR merged with force flag")
+	}
+}
+
+// Текст ревью возвращается в состоянии ревьювера и в истории PR.
+func TestSubmitReviewReturnsBody(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("b"))
+	mustCreatePR(t, s, "pr1", "a")
+
+	pr, err := s.SubmitReview(ctx, "pr1", "b", review.ReviewChangesRequested, "add tests")
+	if err != nil {
+		t.Fatalf("submit review: %v", err)
+	}
+	states := pr.ReviewerStates()
+	if len(states) != 1 || states[0].State != review.ReviewChangesRequested || states[0].Body != "add tests" {
+		t.Fatalf("reviewer states = %+v, want CHANGES_REQUESTED with body", states)
+	}
+
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.Type != review.EventReviewSubmitted || last.Details != "CHANGES_REQUESTED: add tests" {
+		t.Fatalf("review event = %+v, want details with body", last)
+	}
+}
+
+func TestReopenPRRestoresStatusBeforeClose(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	// min_reviewers=0: OPEN PR может остаться без ревьюверов.
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 0, MaxReviewers: 1}, member("a"))
+
+	open := mustCreatePR(t, s, "open", "a")
+	if open.Status != review.StatusOpen || len(open.ReviewerIDs) != 0 {
+		t.Fatalf("created PR = status %s reviewers %v, want OPEN without reviewers", open.Status, open.ReviewerIDs)
+	}
+	draft, err := s.CreatePR(ctx, review.PullRequest{ID: "draft", Title: "t", AuthorID: "a"}, review.CreatePROptions{Draft: true})
+	if err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+
+	for _, pr := range []review.PullRequest{open, draft} {
+		if _, err := s.ClosePR(ctx, pr.ID); err != nil {
+			t.Fatalf("close %s: %v", pr.ID, err)
+		}
+		reopened, err := s.ReopenPR(ctx, pr.ID)
+		if err != nil {
+			t.Fatalf("reopen %s: %v", pr.ID, err)
+		}
+		if reopened.Status != pr.Status || reopened.ClosedAt != nil {
+			t.Fatalf("reopened %s = status %s closed_at %v, want %s", pr.ID, reopened.Status, reopened.ClosedAt, pr.Status)
+		}
+	}
+}
+
+// Ошибка на середине CreateTeam откатывает и команду, и уже добавленных
+// участников.
+func TestCreateTeamIsAtomic(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 2}, member("a"), member("b"))
+
+	_, err := s.CreateTeam(ctx, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 2},
+		[]review.User{member("x"), member("a")})
+	if !errors.Is(err, review.ErrUserInAnotherTeam) {
+		t.Fatalf("create team error = %v, want %v", err, review.ErrUserInAnotherTeam)
+	}
+
+	if _, err := s.GetByName(ctx, "frontend"); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("get rolled back team error = %v, want %v", err, review.ErrNotFound)
+	}
+	if _, err := s.GetUserByID(ctx, "x"); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("get rolled back user error = %v, want %v", err, review.ErrNotFound)
+	}
+	a, err := s.GetUserByID(ctx, "a")
+	if err != nil {
+		t.Fatalf("get user a: %v", err)
+	}
+	if a.Team != "backend" {
+		t.Fatalf("user a team = %q, want backend", a.Team)
+	}
+
+	mustCreateTeam(t, s, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 2}, member("x"), member("y"))
+}
+
+func TestCreatePR(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 2},
+		member("a"), member("b"), member("c"))
+
+	pr := mustCreatePR(t, s, "pr1", "a")
+	if pr.Status != review.StatusOpen || pr.Version != 1 {
+		t.Fatalf("created PR = status %s version %d, want OPEN version 1", pr.Status, pr.Version)
+	}
+	wantReviewers(t, "pr1", pr.ReviewerIDs, "b", "c")
+	for _, id := range pr.ReviewerIDs {
+		if pr.ReviewerTeams[id] != "backend" {
+			t.Fatalf("reviewer %s team = %q, want backend", id, pr.ReviewerTeams[id])
+		}
+	}
+
+	history := func() []review.EventType {
+		t.Helper()
+		events, err := s.GetPRHistory(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("history: %v", err)
+		}
+		var types []review.EventType
+		for _, ev := range events {
+			types = append(types, ev.Type)
+		}
+		return types
+	}
+	want := fmt.Sprint([]review.EventType{review.EventCreated, review.EventReviewerAssigned, review.EventReviewerAssigned})
+	if got := fmt.Sprint(history()); got != want {
+		t.Fatalf("history = %s, want %s", got, want)
+	}
+
+	_, err := s.CreatePR(ctx, review.PullRequest{ID: "pr1", Title: "dup", AuthorID: "b"}, review.CreatePROptions{})
+	if !errors.Is(err, review.ErrPRExists) {
+		t.Fatalf("duplicate create error = %v, want %v", err, review.ErrPRExists)
+	}
+	if got := fmt.Sprint(history()); got != want {
+		t.Fatalf("history after duplicate = %s, want %s", got, want)
+	}
+
+	_, err = s.CreatePR(ctx, review.PullRequest{ID: "pr2", Title: "t", AuthorID: "nobody"}, review.CreatePROptions{})
+	if !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("unknown author error = %v, want %v", err, review.ErrNotFound)
+	}
+}
+
+// История PR хранит каждое изменение по порядку с инициатором и временем;
+// отклонённая операция в историю не попадает.
+func TestPRHistoryRecordsChanges(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1},
+		member("a"), member("b"), member("c"))
+	ctx := review.WithActor(context.Background(), "manager")
+	before := time.Now().UTC()
+
+	pr := mustCreatePR(t, s, "pr1", "a")
+	wantReviewers(t, "pr1", pr.ReviewerIDs, "b")
+	if _, _, err := s.ReassignReviewer(ctx, "pr1", "b"); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if _, _, err := s.ReassignReviewer(ctx, "pr1", "b"); !errors.Is(err, review.ErrNotAssigned) {
+		t.Fatalf("reassign of unassigned error = %v, want %v", err, review.ErrNotAssigned)
+	}
+	if _, err := s.SubmitReview(ctx, "pr1", "c", review.ReviewApproved, ""); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := s.ClosePR(ctx, "pr1"); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := s.ReopenPR(ctx, "pr1"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if _, err := s.MergePR(ctx, "pr1", false); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	want := []review.PREvent{
+		{Type: review.EventCreated},
+		{Type: review.EventReviewerAssigned, NewReviewerID: "b"},
+		{Type: review.EventReviewerReassigned, ActorID: "manager", OldReviewerID: "b", NewReviewerID: "c"},
+		{Type: review.EventReviewSubmitted, ActorID: "manager", Details: "APPROVED"},
+		{Type: review.EventClosed, ActorID: "manager"},
+		{Type: review.EventReopened, ActorID: "manager"},
+		{Type: review.EventMerged, ActorID: "manager"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("history has %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, ev := range events {
+		w := want[i]
+		if ev.PRID != "pr1" || ev.Type != w.Type || ev.ActorID != w.ActorID ||
+			ev.OldReviewerID != w.OldReviewerID || ev.NewReviewerID != w.NewReviewerID {
+			t.Fatalf("event %d = %+v, want %+v", i, ev, w)
+		}
+		if w.Details != "" && ev.Details != w.Details {
+			t.Fatalf("event %d details = %q, want %q", i, ev.Details, w.Details)
+		}
+		if ev.CreatedAt.Before(before) || (i > 0 && ev.ID <= events[i-1].ID) {
+			t.Fatalf("event %d = %+v, want increasing IDs after %v", i, ev, before)
+		}
+	}
+
+	if _, err := s.GetPRHistory(ctx, "missing"); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("history of missing PR error = %v, want %v", err, review.ErrNotFound)
+	}
+}
+
+// Каждое изменение PR увеличивает версию; изменение с устаревшей ожидаемой
+// версией отклоняется с ErrConflict и ничего не меняет.
+func TestPRVersionOptimisticConcurrency(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1},
+		member("a"), member("b"), member("c"), member("d"))
+
+	pr := mustCreatePR(t, s, "pr1", "a")
+	if pr.Version != 1 {
+		t.Fatalf("new PR version = %d, want 1", pr.Version)
+	}
+
+	// Оба клиента прочитали версию 1; первый успевает переназначить ревьювера.
+	stale := review.WithExpectedVersion(ctx, pr.Version)
+	updated, newID, err := s.ReassignReviewer(stale, "pr1", "b")
+	if err != nil {
+		t.Fatalf("first reassign: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Fatalf("version after reassign = %d, want 2", updated.Version)
+	}
+
+	if _, _, err := s.ReassignReviewer(stale, "pr1", newID); !errors.Is(err, review.ErrConflict) {
+		t.Fatalf("stale reassign error = %v, want %v", err, review.ErrConflict)
+	}
+	if _, err := s.MergePR(stale, "pr1", false); !errors.Is(err, review.ErrConflict) {
+		t.Fatalf("stale merge error = %v, want %v", err, review.ErrConflict)
+	}
+	if _, err := s.SubmitReview(stale, "pr1", newID, review.ReviewApproved, ""); !errors.Is(err, review.ErrConflict) {
+		t.Fatalf("stale review error = %v, want %v", err, review.ErrConflict)
+	}
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if last := events[len(events)-1]; len(events) != 3 || last.Type != review.EventReviewerReassigned {
+		t.Fatalf("history = %+v, want stale operations left out", events)
+	}
+
+	closed, err := s.ClosePR(review.WithExpectedVersion(ctx, updated.Version), "pr1")
+	if err != nil {
+		t.Fatalf("close with current version: %v", err)
+	}
+	if closed.Status != review.StatusClosed || closed.Version != 3 {
+		t.Fatalf("closed PR = status %s version %d, want CLOSED version 3", closed.Status, closed.Version)
+	}
+	wantReviewers(t, "pr1", closed.ReviewerIDs, newID)
+}
+
+// Управление составом команды: выведенный участник передаёт OPEN-ревью
+// команде автора (или остаётся на PR, если замены нет), переименование
+// сохраняет участников и назначения, удалить можно только пустую команду.
+func TestTeamMembershipManagement(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("b"))
+	mustCreateTeam(t, s, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 1}, member("f"), member("g"))
+
+	if _, err := s.AddTeamMember(ctx, "backend", member("c")); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	if _, err := s.AddTeamMember(ctx, "backend", member("f")); !errors.Is(err, review.ErrUserInAnotherTeam) {
+		t.Fatalf("add member of another team error = %v, want %v", err, review.ErrUserInAnotherTeam)
+	}
+	if _, err := s.AddTeamMember(ctx, "missing", member("z")); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("add member to missing team error = %v, want %v", err, review.ErrNotFound)
+	}
+
+	wantReviewers(t, "pr1", mustCreatePR(t, s, "pr1", "a").ReviewerIDs, "b")
+	wantReviewers(t, "pr2", mustCreatePR(t, s, "pr2", "f").ReviewerIDs, "g")
+
+	report, err := s.RemoveTeamMember(ctx, "backend", "b")
+	if err != nil {
+		t.Fatalf("remove b: %v", err)
+	}
+	if len(report.Reassigned) != 1 || report.Reassigned[0] != (review.Reassignment{PRID: "pr1", OldReviewerID: "b", NewReviewerID: "c"}) {
+		t.Fatalf("remove b report = %+v, want pr1 b -> c", report)
+	}
+	if _, err := s.RemoveTeamMember(ctx, "backend", "b"); !errors.Is(err, review.ErrNotInTeam) {
+		t.Fatalf("second remove error = %v, want %v", err, review.ErrNotInTeam)
+	}
+
+	// Во frontend, кроме автора, никого нет: g остаётся ревьювером pr2.
+	report, err = s.RemoveTeamMember(ctx, "frontend", "g")
+	if err != nil {
+		t.Fatalf("remove g: %v", err)
+	}
+	if len(report.Reassigned) != 0 || len(report.Failed) != 1 || report.Failed[0].PRID != "pr2" ||
+		report.Failed[0].Reason != review.ErrNoCandidate.Error() {
+		t.Fatalf("remove g report = %+v, want pr2 failed with NO_CANDIDATE", report)
+	}
+	assigned, err := s.GetAssignedForUser(ctx, "g")
+	if err != nil {
+		t.Fatalf("assigned for g: %v", err)
+	}
+	if len(assigned) != 1 || assigned[0].ID != "pr2" {
+		t.Fatalf("assigned to g = %+v, want pr2", assigned)
+	}
+
+	if _, err := s.RenameTeam(ctx, "backend", "frontend"); !errors.Is(err, review.ErrTeamExists) {
+		t.Fatalf("rename to existing error = %v, want %v", err, review.ErrTeamExists)
+	}
+	if _, err := s.RenameTeam(ctx, "backend", "platform"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	members, err := s.ListByTeam(ctx, "platform")
+	if err != nil {
+		t.Fatalf("list platform: %v", err)
+	}
+	if len(members) != 2 || members[0].ID != "a" || members[1].ID != "c" {
+		t.Fatalf("platform members = %+v, want a and c", members)
+	}
+	wantReviewers(t, "pr3", mustCreatePR(t, s, "pr3", "a").ReviewerIDs, "c")
+
+	if err := s.DeleteTeam(ctx, "platform"); !errors.Is(err, review.ErrTeamNotEmpty) {
+		t.Fatalf("delete non-empty error = %v, want %v", err, review.ErrTeamNotEmpty)
+	}
+	if _, err := s.RemoveTeamMember(ctx, "frontend", "f"); err != nil {
+		t.Fatalf("remove f: %v", err)
+	}
+	if err := s.DeleteTeam(ctx, "frontend"); err != nil {
+		t.Fatalf("delete empty team: %v", err)
+	}
+	if _, err := s.GetByName(ctx, "frontend"); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("deleted team lookup error = %v, want %v", err, review.ErrNotFound)
+	}
+}
+
+// Переведённый в другую команду пользователь передаёт OPEN-ревью
+// кандидатам из команды автора PR; PR без кандидата остаются за ним.
+func TestMoveUserToTeamReassignsOpenReviews(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("b"))
+	mustCreateTeam(t, s, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 1}, member("f"), member("g"), member("h"))
+	wantReviewers(t, "pr1", mustCreatePR(t, s, "pr1", "f").ReviewerIDs, "g")
+
+	u, report, err := s.MoveUserToTeam(ctx, "g", "backend")
+	if err != nil {
+		t.Fatalf("move g: %v", err)
+	}
+	if u.Team != "backend" {
+		t.Fatalf("moved user team = %q, want backend", u.Team)
+	}
+	if len(report.Reassigned) != 1 || report.Reassigned[0] != (review.Reassignment{PRID: "pr1", OldReviewerID: "g", NewReviewerID: "h"}) {
+		t.Fatalf("move g report = %+v, want pr1 g -> h", report)
+	}
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if last := events[len(events)-1]; !strings.HasPrefix(last.Details, "moved from team frontend to backend; ") {
+		t.Fatalf("reassignment details = %q, want move cause", last.Details)
+	}
+
+	// Во frontend остался только автор pr1.
+	_, report, err = s.MoveUserToTeam(ctx, "h", "backend")
+	if err != nil {
+		t.Fatalf("move h: %v", err)
+	}
+	if len(report.Reassigned) != 0 || len(report.Failed) != 1 ||
+		report.Failed[0] != (review.ReassignmentFailure{PRID: "pr1", ReviewerID: "h", Reason: review.ErrNoCandidate.Error()}) {
+		t.Fatalf("move h report = %+v, want pr1 failed with NO_CANDIDATE", report)
+	}
+
+	if _, _, err := s.MoveUserToTeam(ctx, "h", "missing"); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("move to missing team error = %v, want %v", err, review.ErrNotFound)
+	}
+	if _, report, err := s.MoveUserToTeam(ctx, "h", "backend"); err != nil || len(report.Reassigned)+len(report.Failed) != 0 {
+		t.Fatalf("move to own team = %+v, %v; want no changes", report, err)
+	}
+	assigned, err := s.GetAssignedForUser(ctx, "h")
+	if err != nil {
+		t.Fatalf("assigned for h: %v", err)
+	}
+	if len(assigned) != 1 || assigned[0].ID != "pr1" {
+		t.Fatalf("assigned to h = %+v, want pr1", assigned)
+	}
+}
+
+// Деактивация с reassign передаёт только OPEN-ревью пользователя другим
+// активным кандидатам; без флага назначения не меняются.
+func TestSetUserActiveReassignsOpenReviews(t *testing.T) {
+	s := newServiceWithPolicy(t, review.NewRoundRobinSelector(), review.MergePolicy{})
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1},
+		member("a"), member("b"), member("c"), member("d"))
+	mustCreateTeam(t, s, review.Team{Name: "solo", MinReviewers: 0, MaxReviewers: 1}, member("s"))
+
+	wantReviewers(t, "open", mustCreatePR(t, s, "open", "a").ReviewerIDs, "b")
+	wantReviewers(t, "merged", mustCreatePR(t, s, "merged", "a").ReviewerIDs, "c")
+	if _, err := s.MergePR(ctx, "merged", false); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	// Без reassign c просто выключается.
+	u, report, err := s.SetUserActive(ctx, "c", false, false)
+	if err != nil {
+		t.Fatalf("deactivate c: %v", err)
+	}
+	if u.IsActive || len(report.Reassigned)+len(report.Failed) != 0 {
+		t.Fatalf("deactivate c = %+v, %+v; want inactive without reassignments", u, report)
+	}
+	if _, _, err := s.SetUserActive(ctx, "c", true, false); err != nil {
+		t.Fatalf("reactivate c: %v", err)
+	}
+
+	_, report, err = s.SetUserActive(ctx, "b", false, true)
+	if err != nil {
+		t.Fatalf("deactivate b: %v", err)
+	}
+	if len(report.Reassigned) != 1 || report.Reassigned[0].PRID != "open" || report.Reassigned[0].OldReviewerID != "b" {
+		t.Fatalf("deactivate b report = %+v, want open reassigned", report)
+	}
+	if newID := report.Reassigned[0].NewReviewerID; newID != "c" && newID != "d" {
+		t.Fatalf("replacement = %s, want an active member of backend", newID)
+	}
+
+	_, report, err = s.SetUserActive(ctx, "c", false, true)
+	if err != nil {
+		t.Fatalf("deactivate c with reassign: %v", err)
+	}
+	for _, r := range report.Reassigned {
+		if r.PRID == "merged" {
+			t.Fatalf("merged PR reassigned: %+v", report)
+		}
+	}
+	assigned, err := s.GetAssignedForUser(ctx, "c")
+	if err != nil {
+		t.Fatalf("assigned for c: %v", err)
+	}
+	if len(assigned) != 1 || assigned[0].ID != "merged" {
+		t.Fatalf("assigned to c = %+v, want only merged", assigned)
+	}
+
+	// Кроме автора и t, в solo никого нет, поэтому замены для t не находится.
+	if _, err := s.AddTeamMember(ctx, "solo", member("t")); err != nil {
+		t.Fatalf("add t: %v", err)
+	}
+	wantReviewers(t, "solo-pr", mustCreatePR(t, s, "solo-pr", "s").ReviewerIDs, "t")
+	_, report, err = s.SetUserActive(ctx, "t", false, true)
+	if err != nil {
+		t.Fatalf("deactivate t: %v", err)
+	}
+	if len(report.Failed) != 1 || report.Failed[0] != (review.ReassignmentFailure{PRID: "solo-pr", ReviewerID: "t", Reason: review.ErrNoCandidate.Error()}) {
+		t.Fatalf("deactivate t report = %+v, want solo-pr failed with NO_CANDIDATE", report)
+	}
+}
+
+// Пользователь в текущем отсутствии не назначается ни при создании PR, ни
+// при переназначении; будущие и прошедшие отсутствия назначению не мешают.
+func TestAbsentReviewersAreSkipped(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1},
+		member("a"), member("b"), member("c"))
+	now := time.Now().UTC()
+	for _, a := range []review.Absence{
+		{UserID: "b", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Reason: "vacation"},
+		{UserID: "c", StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(48 * time.Hour), Reason: "conference"},
+		{UserID: "c", StartsAt: now.Add(-48 * time.Hour), EndsAt: now.Add(-24 * time.Hour)},
+	} {
+		if _, err := s.AddAbsence(ctx, a); err != nil {
+			t.Fatalf("add absence: %v", err)
+		}
+	}
+	if _, err := s.AddAbsence(ctx, review.Absence{UserID: "b", StartsAt: now, EndsAt: now}); !errors.Is(err, review.ErrInvalidAbsence) {
+		t.Fatalf("empty absence error = %v, want %v", err, review.ErrInvalidAbsence)
+	}
+	if _, err := s.AddAbsence(ctx, review.Absence{UserID: "nobody", StartsAt: now, EndsAt: now.Add(time.Hour)}); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("absence of unknown user error = %v, want %v", err, review.ErrNotFound)
+	}
+
+	wantReviewers(t, "pr1", mustCreatePR(t, s, "pr1", "a").ReviewerIDs, "c")
+	if _, _, err := s.ReassignReviewer(ctx, "pr1", "c"); !errors.Is(err, review.ErrNoCandidate) {
+		t.Fatalf("reassign to absent error = %v, want %v", err, review.ErrNoCandidate)
+	}
+	if u, err := s.GetUserByID(ctx, "b"); err != nil || !u.IsActive {
+		t.Fatalf("absent user = %+v, %v; want still active", u, err)
+	}
+
+	absences, err := s.ListTeamAbsences(ctx, "backend")
+	if err != nil {
+		t.Fatalf("list absences: %v", err)
+	}
+	if len(absences) != 2 || absences[0].UserID != "b" || absences[1].UserID != "c" || absences[1].Reason != "conference" {
+		t.Fatalf("absences = %+v, want current b and upcoming c", absences)
+	}
+	if _, err := s.ListTeamAbsences(ctx, "missing"); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("absences of missing team error = %v, want %v", err, review.ErrNotFound)
+	}
+}
+
+// Предпросмотр объясняет статус каждого участника команды, выбирает тех же
+// ревьюверов, что и CreatePR, и ничего не сохраняет.
+func TestPreviewAssignmentExplainsCandidates(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("cap"))
+	wantReviewers(t, "pr0", mustCreatePR(t, s, "pr0", "a").ReviewerIDs, "cap")
+	if _, err := s.SetUserMaxOpenReviews(ctx, "cap", 1); err != nil {
+		t.Fatalf("set max open reviews: %v", err)
+	}
+	for _, id := range []string{"ex", "gone", "off", "s1", "s2"} {
+		if _, err := s.AddTeamMember(ctx, "backend", member(id)); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+	}
+	if _, _, err := s.SetUserActive(ctx, "off", false, false); err != nil {
+		t.Fatalf("deactivate off: %v", err)
+	}
+	now := time.Now().UTC()
+	if _, err := s.AddAbsence(ctx, review.Absence{UserID: "gone", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("add absence: %v", err)
+	}
+	if _, err := s.SetReviewerRules(ctx, "backend", []review.ReviewerRule{
+		{AuthorID: "a", ReviewerID: "ex", Type: review.RuleExclude},
+	}); err != nil {
+		t.Fatalf("set rules: %v", err)
+	}
+
+	pr := review.PullRequest{ID: "pr1", Title: "t", AuthorID: "a"}
+	preview, err := s.PreviewAssignment(ctx, pr, review.CreatePROptions{})
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if preview.Err != nil || preview.TeamName != "backend" || len(preview.ReviewerIDs) != 1 {
+		t.Fatalf("preview = %+v, want one reviewer from backend", preview)
+	}
+	picked := preview.ReviewerIDs[0]
+	other := map[string]string{"s1": "s2", "s2": "s1"}[picked]
+	if other == "" {
+		t.Fatalf("preview picked %s, want s1 or s2", picked)
+	}
+	want := map[string]review.CandidateStatus{
+		"a":    review.CandidateAuthor,
+		"cap":  review.CandidateAtCapacity,
+		"ex":   review.CandidateExcludedByRule,
+		"gone": review.CandidateAbsent,
+		"off":  review.CandidateInactive,
+		picked: review.CandidateSelected,
+		other:  review.CandidateNotSelected,
+	}
+	if len(preview.Candidates) != len(want) {
+		t.Fatalf("candidates = %+v, want %d", preview.Candidates, len(want))
+	}
+	for _, c := range preview.Candidates {
+		if c.Status != want[c.UserID] || c.TeamName != "backend" {
+			t.Fatalf("candidate %s = %+v, want status %s", c.UserID, c, want[c.UserID])
+		}
+		if c.UserID == "cap" && (c.OpenReviews != 1 || c.MaxOpenReviews != 1) {
+			t.Fatalf("cap load = %d of %d, want 1 of 1", c.OpenReviews, c.MaxOpenReviews)
+		}
+	}
+
+	again, err := s.PreviewAssignment(ctx, pr, review.CreatePROptions{})
+	if err != nil {
+		t.Fatalf("second preview: %v", err)
+	}
+	wantReviewers(t, "second preview", again.ReviewerIDs, picked)
+	if _, err := s.GetPRHistory(ctx, "pr1"); !errors.Is(err, review.ErrNotFound) {
+		t.Fatalf("previewed PR lookup error = %v, want %v", err, review.ErrNotFound)
+	}
+	wantReviewers(t, "pr1", mustCreatePR(t, s, "pr1", "a").ReviewerIDs, picked)
+
+	mustCreateTeam(t, s, review.Team{Name: "pair", MinReviewers: 2, MaxReviewers: 2}, member("x"), member("y"))
+	short, err := s.PreviewAssignment(ctx, review.PullRequest{AuthorID: "x"}, review.CreatePROptions{})
+	if err != nil {
+		t.Fatalf("preview in pair: %v", err)
+	}
+	if !errors.Is(short.Err, review.ErrNotEnoughReviewers) {
+		t.Fatalf("preview error = %v, want %v", short.Err, review.ErrNotEnoughReviewers)
+	}
+	wantReviewers(t, "short preview", short.ReviewerIDs, "y")
+	if _, err := s.CreatePR(ctx, review.PullRequest{ID: "pr2", Title: "t", AuthorID: "x"}, review.CreatePROptions{}); !errors.Is(err, review.ErrNotEnoughReviewers) {
+		t.Fatalf("create error = %v, want %v", err, review.ErrNotEnoughReviewers)
+	}
+}
+
+// Замена при массовой деактивации берётся из команды автора PR, а не из
+// команды деактивированного ревьювера.
+func TestDeactivateTeamUsersReplacesFromAuthorsTeam(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("b"))
+	mustCreateTeam(t, s, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 1}, member("f1"), member("f2"))
+	if _, err := s.SetOwnershipRules(ctx, "web", []review.OwnershipRule{{Pattern: "/api/", Owners: []string{"b"}}}); err != nil {
+		t.Fatalf("set ownership rules: %v", err)
+	}
+
+	pr, err := s.CreatePR(ctx, review.PullRequest{ID: "pr1", Title: "t", AuthorID: "f1",
+		Repository: "web", ChangedFiles: []string{"api/client.go"}}, review.CreatePROptions{})
+	if err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+	wantReviewers(t, "pr1", pr.ReviewerIDs, "b")
+
+	report, err := s.DeactivateTeamUsers(ctx, "backend", []string{"b"})
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if len(report.Reassigned) != 1 || report.Reassigned[0].NewReviewerID != "f2" || len(report.Failed) != 0 {
+		t.Fatalf("report = %+v, want b replaced by f2", report)
+	}
+	got, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if last := got[len(got)-1]; last.Type != review.EventReviewerReassigned || last.NewReviewerID != "f2" {
+		t.Fatalf("last event = %+v, want reassignment to f2", last)
+	}
+}
+
+func TestDeactivateTeamUsersManyPRs(t *testing.T) {
+	s := newService(t, review.NewLeastLoadedSelector(rand.New(rand.NewSource(1))))
+	ctx := context.Background()
+
+	var members []review.User
+	for i := range 20 {
+		members = append(members, member(fmt.Sprintf("u%02d", i)))
+	}
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 2, MaxReviewers: 2}, members...)
+	const prs = 400
+	for i := range prs {
+		mustCreatePR(t, s, fmt.Sprintf("pr%03d", i), members[i%len(members)].ID)
+	}
+
+	start := time.Now()
+	report, err := s.DeactivateTeamUsers(ctx, "backend", []string{"u00", "u01", "u02", "u03", "u04"})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if len(report.Reassigned) == 0 || len(report.Failed) != 0 {
+		t.Fatalf("reassigned %d, failed %d; want all reassigned", len(report.Reassigned), len(report.Failed))
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Fatalf("deactivation of %d reviews took %v", len(report.Reassigned), elapsed)
+	}
+
+	for _, id := range []string{"u00", "u04"} {
+		assigned, err := s.GetAssignedForUser(ctx, id)
+		if err != nil {
+			t.Fatalf("assigned for %s: %v", id, err)
+		}
+		for _, pr := range assigned {
+			if pr.Status == review.StatusOpen {
+				t.Fatalf("deactivated %s still reviews open %s", id, pr.ID)
+			}
+		}
+	}
+}
+
+// countingUsers и countingTeams считают обращения к хранилищу, число которых
+// не должно зависеть от числа PR и авторов.
+type countingUsers struct {
+	review.UserRepository
+	getByID, listByIDs int
+}
+
+func (r *countingUsers) GetByID(ctx context.Context, id string) (review.User, error) {
+	r.getByID++
+	return r.UserRepository.GetByID(ctx, id)
+}
+
+func (r *countingUsers) ListByIDs(ctx context.Context, ids []string) ([]review.User, error) {
+	r.listByIDs++
+	return r.UserRepository.ListByIDs(ctx, ids)
+}
+
+type countingTeams struct {
+	review.TeamRepository
+	rules, fallbacks int
+}
+
+func (r *countingTeams) ListReviewerRules(ctx context.Context, teamName string) ([]review.ReviewerRule, error) {
+	r.rules++
+	return r.TeamRepository.ListReviewerRules(ctx, teamName)
+}
+
+func (r *countingTeams) ListFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	r.fallbacks++
+	return r.TeamRepository.ListFallbacks(ctx, teamName)
+}
+
+// Авторы читаются одним запросом, а резерв и правила — один раз на команду
+// авторов, сколько бы авторов и PR ни затронула деактивация.
+func TestDeactivateTeamUsersLoadsAuthorsAndRulesOnce(t *testing.T) {
+	st := memory.NewStore()
+	users := &countingUsers{UserRepository: memory.NewUserRepo(st)}
+	teams := &countingTeams{TeamRepository: memory.NewTeamRepo(st)}
+	prs := memory.NewPRRepo(st)
+	s := review.NewService(prs, users, teams, memory.NewTxManager(st),
+		review.Selectors{Default: review.NewRoundRobinSelector()}, review.MergePolicy{},
+		rand.New(rand.NewSource(1)), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	var members []review.User
+	for i := range 10 {
+		members = append(members, member(fmt.Sprintf("u%02d", i)))
+	}
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 2, MaxReviewers: 2}, members...)
+	if _, err := s.SetReviewerRules(ctx, "backend", []review.ReviewerRule{
+		{AuthorID: "u05", ReviewerID: "u06", Type: review.RuleExclude},
+	}); err != nil {
+		t.Fatalf("set rules: %v", err)
+	}
+	for i := range 50 {
+		mustCreatePR(t, s, fmt.Sprintf("pr%02d", i), members[1+i%9].ID)
+	}
+
+	users.getByID, users.listByIDs, teams.rules, teams.fallbacks = 0, 0, 0, 0
+	report, err := s.DeactivateTeamUsers(ctx, "backend", []string{"u00"})
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if len(report.Reassigned) == 0 || len(report.Failed) != 0 {
+		t.Fatalf("reassigned %d, failed %d; want all reassigned", len(report.Reassigned), len(report.Failed))
+	}
+	if users.getByID != 0 || users.listByIDs != 1 || teams.rules != 1 || teams.fallbacks != 1 {
+		t.Fatalf("GetByID %d, ListByIDs %d, ListReviewerRules %d, ListFallbacks %d; want 0, 1, 1, 1",
+			users.getByID, users.listByIDs, teams.rules, teams.fallbacks)
+	}
+	for _, r := range report.Reassigned {
+		pr, err := prs.GetByID(ctx, r.PRID)
+		if err != nil {
+			t.Fatalf("get %s: %v", r.PRID, err)
+		}
+		if pr.AuthorID == "u05" && r.NewReviewerID == "u06" {
+			t.Fatalf("%s of u05 reassigned to excluded u06", r.PRID)
+		}
+	}
+}
+
+// Повторное добавление пользователя без команды через /team/add не
+// сбрасывает его личный лимит и уровень, если запрос их не задаёт.
+func TestCreateTeamKeepsExistingUserLimit(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("b"), member("c"))
+	if _, err := s.SetUserMaxOpenReviews(ctx, "b", 3); err != nil {
+		t.Fatalf("set max open reviews: %v", err)
+	}
+	if _, err := s.SetUserMaxOpenReviews(ctx, "c", 3); err != nil {
+		t.Fatalf("set max open reviews: %v", err)
+	}
+	if _, err := s.SetUserSeniority(ctx, "b", review.SenioritySenior); err != nil {
+		t.Fatalf("set seniority: %v", err)
+	}
+	for _, id := range []string{"b", "c"} {
+		if _, err := s.RemoveTeamMember(ctx, "backend", id); err != nil {
+			t.Fatalf("remove %s: %v", id, err)
+		}
+	}
+
+	c := member("c")
+	c.MaxOpenReviews = 5
+	mustCreateTeam(t, s, review.Team{Name: "platform", MinReviewers: 1, MaxReviewers: 1}, member("b"), c)
+
+	for id, want := range map[string]int{"b": 3, "c": 5} {
+		u, err := s.GetUserByID(ctx, id)
+		if err != nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		if u.Team != "platform" || u.MaxOpenReviews != want {
+			t.Fatalf("user %s = team %q max_open_reviews %d, want platform %d", id, u.Team, u.MaxOpenReviews, want)
+		}
+	}
+	b, err := s.GetUserByID(ctx, "b")
+	if err != nil {
+		t.Fatalf("get b: %v", err)
+	}
+	if b.Seniority != review.SenioritySenior || b.Name != "name-b" {
+		t.Fatalf("user b = seniority %q name %q, want SENIOR name-b", b.Seniority, b.Name)
+	}
+}
+
+// Без кандидатов в команде автора массовая деактивация берёт замену из
+// резервной команды и сохраняет её как команду ревьювера.
+func TestDeactivateTeamUsersUsesFallbackTeams(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("b"))
+	mustCreateTeam(t, s, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 1}, member("f1"))
+	mustCreateTeam(t, s, review.Team{Name: "mobile", MinReviewers: 1, MaxReviewers: 1}, member("m1"))
+	if _, err := s.SetTeamFallbacks(ctx, "backend", []string{"frontend", "mobile"}); err != nil {
+		t.Fatalf("set fallbacks: %v", err)
+	}
+	if _, _, err := s.SetUserActive(ctx, "f1", false, false); err != nil {
+		t.Fatalf("deactivate f1: %v", err)
+	}
+	wantReviewers(t, "pr1", mustCreatePR(t, s, "pr1", "a").ReviewerIDs, "b")
+
+	report, err := s.DeactivateTeamUsers(ctx, "backend", []string{"b"})
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if len(report.Reassigned) != 1 || report.Reassigned[0].NewReviewerID != "m1" {
+		t.Fatalf("report = %+v, want b replaced by m1", report)
+	}
+
+	assigned, err := s.GetAssignedForUser(ctx, "m1")
+	if err != nil {
+		t.Fatalf("assigned for m1: %v", err)
+	}
+	if len(assigned) != 1 || assigned[0].ReviewerTeams["m1"] != "mobile" {
+		t.Fatalf("assigned to m1 = %+v, want pr1 with reviewer team mobile", assigned)
+	}
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	want := "bulk deactivation in team backend; fallback team mobile; "
+	if last := events[len(events)-1]; !strings.HasPrefix(last.Details, want) {
+		t.Fatalf("reassignment details = %q, want prefix %q", last.Details, want)
+	}
+}
+
+// Метки не отдают места резервной команде, пока команда автора может их
+// занять; на оставшиеся места из резерва метки учитываются.
+// Ревьювера из резервной команды, которому там нет замены, заменяет участник
+// команды автора, а не NO_CANDIDATE.
+func TestReassignFallbackReviewerFromAuthorsTeam(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 2}, member("a"), member("b"))
+	mustCreateTeam(t, s, review.Team{Name: "mobile", MinReviewers: 1, MaxReviewers: 1}, member("m1"))
+	if _, err := s.SetTeamFallbacks(ctx, "backend", []string{"mobile"}); err != nil {
+		t.Fatalf("set fallbacks: %v", err)
+	}
+	pr := mustCreatePR(t, s, "pr1", "a")
+	wantReviewers(t, "pr1", pr.ReviewerIDs, "b", "m1")
+	if pr.ReviewerTeams["m1"] != "mobile" {
+		t.Fatalf("reviewer teams = %v, want m1 from mobile", pr.ReviewerTeams)
+	}
+
+	if _, err := s.AddTeamMember(ctx, "backend", member("c")); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	updated, newID, err := s.ReassignReviewer(ctx, "pr1", "m1")
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if newID != "c" || updated.ReviewerTeams["c"] != "backend" {
+		t.Fatalf("replacement = %s from %q, want c from backend", newID, updated.ReviewerTeams["c"])
+	}
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if last := events[len(events)-1]; strings.Contains(last.Details, "fallback team") {
+		t.Fatalf("reassignment details = %q, want no fallback mark", last.Details)
+	}
+}
+
+// Предпросмотр и создание PR автора без команды отклоняются одной ошибкой;
+// черновик создать можно, но перевести его в OPEN — нет.
+func TestTeamlessAuthorPreviewAndCreate(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1}, member("a"), member("b"))
+	if _, err := s.RemoveTeamMember(ctx, "backend", "a"); err != nil {
+		t.Fatalf("remove a: %v", err)
+	}
+
+	pr := review.PullRequest{ID: "pr1", Title: "t", AuthorID: "a"}
+	if _, err := s.PreviewAssignment(ctx, pr, review.CreatePROptions{}); !errors.Is(err, review.ErrNotInTeam) {
+		t.Fatalf("preview error = %v, want %v", err, review.ErrNotInTeam)
+	}
+	if _, err := s.CreatePR(ctx, pr, review.CreatePROptions{}); !errors.Is(err, review.ErrNotInTeam) {
+		t.Fatalf("create error = %v, want %v", err, review.ErrNotInTeam)
+	}
+
+	if _, err := s.CreatePR(ctx, pr, review.CreatePROptions{Draft: true}); err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	if _, err := s.ReadyPR(ctx, "pr1", 0); !errors.Is(err, review.ErrNotInTeam) {
+		t.Fatalf("ready error = %v, want %v", err, review.ErrNotInTeam)
+	}
+}
+
+func TestCreatePRLabelsPreferHomeTeamOverFallback(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 3}, member("a"), member("b"), member("c"))
+	mustCreateTeam(t, s, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 1}, member("h"), member("z"))
+	if _, err := s.SetTeamFallbacks(ctx, "backend", []string{"frontend"}); err != nil {
+		t.Fatalf("set fallbacks: %v", err)
+	}
+	if _, err := s.SetUserTags(ctx, "z", []string{"go"}); err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+
+	pr, err := s.CreatePR(ctx, review.PullRequest{ID: "pr1", Title: "t", AuthorID: "a", Labels: []string{"go"}},
+		review.CreatePROptions{ReviewersCount: 2})
+	if err != nil {
+		t.Fatalf("create pr1: %v", err)
+	}
+	wantReviewers(t, "pr1", pr.ReviewerIDs, "b", "c")
+	if fmt.Sprint(pr.UnmatchedLabels) != "[go]" {
+		t.Fatalf("pr1 unmatched labels = %v, want [go]", pr.UnmatchedLabels)
+	}
+
+	pr, err = s.CreatePR(ctx, review.PullRequest{ID: "pr2", Title: "t", AuthorID: "a", Labels: []string{"go"}},
+		review.CreatePROptions{ReviewersCount: 3})
+	if err != nil {
+		t.Fatalf("create pr2: %v", err)
+	}
+	wantReviewers(t, "pr2", pr.ReviewerIDs, "b", "c", "z")
+	if pr.ReviewerTeams["z"] != "frontend" || len(pr.UnmatchedLabels) != 0 {
+		t.Fatalf("pr2 = teams %v unmatched %v, want z from frontend and no unmatched labels", pr.ReviewerTeams, pr.UnmatchedLabels)
+	}
+}
+
+// Квоты ролей закрываются командой автора; резервная команда подключается
+// только к местам, которые команда автора не заняла.
+func TestCreatePRRoleQuotasPreferHomeTeamOverFallback(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 3}, member("a"), member("b"), member("c"))
+	mustCreateTeam(t, s, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 1}, member("h"), member("s"))
+	if _, err := s.SetTeamFallbacks(ctx, "backend", []string{"frontend"}); err != nil {
+		t.Fatalf("set fallbacks: %v", err)
+	}
+	if _, err := s.SetUserSeniority(ctx, "s", review.SenioritySenior); err != nil {
+		t.Fatalf("set seniority: %v", err)
+	}
+	if _, err := s.SetTeamRoleQuotas(ctx, "backend", []review.RoleQuota{{Seniority: review.SenioritySenior, Count: 1}}); err != nil {
+		t.Fatalf("set role quotas: %v", err)
+	}
+
+	pr := mustCreatePR(t, s, "pr1", "a")
+	wantReviewers(t, "pr1", pr.ReviewerIDs, "b", "c", "s")
+	if pr.ReviewerTeams["s"] != "frontend" {
+		t.Fatalf("pr1 reviewer teams = %v, want s from frontend", pr.ReviewerTeams)
+	}
+
+	pr, err := s.CreatePR(ctx, review.PullRequest{ID: "pr2", Title: "t", AuthorID: "a"}, review.CreatePROptions{ReviewersCount: 2})
+	if err != nil {
+		t.Fatalf("create pr2: %v", err)
+	}
+	wantReviewers(t, "pr2", pr.ReviewerIDs, "b", "c")
+}
+
+// Массовая деактивация соблюдает правила подбора автора PR.
+func TestDeactivateTeamUsersAppliesReviewerRules(t *testing.T) {
+	s := newService(t, review.NewRoundRobinSelector())
+	ctx := context.Background()
+	mustCreateTeam(t, s, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 1},
+		member("a"), member("b"), member("c"), member("d"), member("e"), member("x"))
+	wantReviewers(t, "pr1", mustCreatePR(t, s, "pr1", "a").ReviewerIDs, "b")
+	wantReviewers(t, "pr2", mustCreatePR(t, s, "pr2", "x").ReviewerIDs, "a")
+
+	_, err := s.SetReviewerRules(ctx, "backend", []review.ReviewerRule{
+		{AuthorID: "a", ReviewerID: "c", Type: review.RuleExclude},
+		{AuthorID: "a", ReviewerID: "e", Type: review.RulePrefer},
+	})
+	if err != nil {
+		t.Fatalf("set reviewer rules: %v", err)
+	}
+
+	report, err := s.DeactivateTeamUsers(ctx, "backend", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	got := map[string]string{}
+	for _, r := range report.Reassigned {
+		got[r.PRID] = r.NewReviewerID
+	}
+	// Правила автора a не действуют на PR автора x.
+	want := map[string]string{"pr1": "e", "pr2": "c"}
+	if fmt.Sprint(got) != fmt.Sprint(want) || len(report.Failed) != 0 {
+		t.Fatalf("report = %+v, want replacements %v", report, want)
+	}
+
+	events, err := s.GetPRHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if last := events[len(events)-1]; !strings.Contains(last.Details, "preferred by rule; ") {
+		t.Fatalf("reassignment details = %q, want preferred by rule", last.Details)
+	}
+}
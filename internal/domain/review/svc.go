@@ -1,28 +1,59 @@
 package review
 
 import (
+	"context"
 	"log/slog"
 	"math/rand"
 	"time"
 )
 
 type Service struct {
-	prRepo   PRRepository
-	userRepo UserRepository
-	teamRepo TeamRepository
-	randSrc  *rand.Rand
-	log      *slog.Logger
+	prRepo    PRRepository
+	userRepo  UserRepository
+	teamRepo  TeamRepository
+	txm       TxManager
+	selectors Selectors
+	mergePol  MergePolicy
+	randSrc   *rand.Rand
+	log       *slog.Logger
 }
 
-func NewService(prRepo PRRepository, userRepo UserRepository, teamRepo TeamRepository, randSrc *rand.Rand, l *slog.Logger) *Service {
+func NewService(prRepo PRRepository, userRepo UserRepository, teamRepo TeamRepository, txm TxManager, selectors Selectors, mergePol MergePolicy, randSrc *rand.Rand, l *slog.Logger) *Service {
+	if txm == nil {
+		txm = noTx{}
+	}
 	if randSrc == nil {
 		randSrc = rand.New(rand.NewSource(time.Now().UnixNano()))
 	}
+	if selectors.Default == nil {
+		selectors.Default = NewRandomSelector(randSrc)
+	}
 	return &Service{
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		teamRepo: teamRepo,
-		randSrc:  randSrc,
-		log:      l,
+		prRepo:    prRepo,
+		userRepo:  userRepo,
+		teamRepo:  teamRepo,
+		txm:       txm,
+		selectors: selectors,
+		mergePol:  mergePol,
+		randSrc:   randSrc,
+		log:       l,
 	}
 }
+
+// noTx используется, если хранилище не поддерживает транзакции.
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// inTx выполняет fn в транзакции s.txm и возвращает её результат.
+func inTx[T any](ctx context.Context, s *Service, fn func(ctx context.Context) (T, error)) (T, error) {
+	var res T
+	err := s.txm.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = fn(ctx)
+		return err
+	})
+	return res, err
+}
@@ -38,9 +38,35 @@ func (s *Service) ListByTeam(ctx context.Context, teamName string) ([]User, erro
 	return users, nil
 }
 
-func (s *Service) CreateTeam(ctx context.Context, name string, members []User) (Team, error) {
+func (s *Service) CreateTeam(ctx context.Context, team Team, members []User) (Team, error) {
+	return inTx(ctx, s, func(ctx context.Context) (Team, error) {
+		return s.createTeam(ctx, team, members)
+	})
+}
+
+func (s *Service) createTeam(ctx context.Context, team Team, members []User) (Team, error) {
+	name := team.Name
 	s.log.Info("CreateTeam called", "team", name, "members_count", len(members))
 
+	if err := team.ValidateReviewersLimits(); err != nil {
+		s.log.Warn("invalid reviewers limits", "team", name, "min", team.MinReviewers, "max", team.MaxReviewers)
+		return Team{}, err
+	}
+	if err := validateMaxOpenReviews(team.MaxOpenReviews); err != nil {
+		s.log.Warn("invalid max open reviews", "team", name, "max_open_reviews", team.MaxOpenReviews)
+		return Team{}, err
+	}
+	for _, u := range members {
+		if err := validateMaxOpenReviews(u.MaxOpenReviews); err != nil {
+			s.log.Warn("invalid max open reviews", "user_id", u.ID, "max_open_reviews", u.MaxOpenReviews)
+			return Team{}, err
+		}
+		if err := validateSeniority(u.Seniority); err != nil {
+			s.log.Warn("invalid seniority", "user_id", u.ID, "seniority", u.Seniority)
+			return Team{}, err
+		}
+	}
+
 	_, err := s.teamRepo.GetByName(ctx, name)
 	if err == nil {
 		s.log.Warn("team already exists", "team", name)
@@ -52,8 +78,6 @@ func (s *Service) CreateTeam(ctx context.Context, name string, members []User) (
 	}
 	s.log.Info("team does not exist, proceeding", "team", name)
 
-	team := Team{Name: name}
-
 	if _, err := s.teamRepo.Create(ctx, team); err != nil {
 		s.log.Error("failed to create team", "team", name, "error", err)
 		return Team{}, err
@@ -73,6 +97,15 @@ func (s *Service) CreateTeam(ctx context.Context, name string, members []User) (
 			if existing.Team != "" && existing.Team != name {
 				return Team{}, ErrUserInAnotherTeam
 			}
+			if u.Name == "" {
+				u.Name = existing.Name
+			}
+			if u.MaxOpenReviews == 0 {
+				u.MaxOpenReviews = existing.MaxOpenReviews
+			}
+			if u.Seniority == "" {
+				u.Seniority = existing.Seniority
+			}
 			if _, err := s.userRepo.Update(ctx, u); err != nil {
 				s.log.Error("failed to update user", "user_id", u.ID, "error", err)
 				return Team{}, err
@@ -104,3 +137,410 @@ func (s *Service) GetByName(ctx context.Context, name string) (Team, error) {
 	s.log.Info("GetByName completed", "team", name)
 	return team, nil
 }
+
+// SetTeamMaxOpenReviews задаёт лимит OPEN-ревью на участника команды по
+// умолчанию; 0 снимает лимит. Уже назначенные ревью не переназначаются.
+func (s *Service) SetTeamMaxOpenReviews(ctx context.Context, teamName string, maxOpen int) (Team, error) {
+	return inTx(ctx, s, func(ctx context.Context) (Team, error) {
+		s.log.Info("SetTeamMaxOpenReviews called", "team", teamName, "max_open_reviews", maxOpen)
+
+		if err := validateMaxOpenReviews(maxOpen); err != nil {
+			s.log.Warn("invalid max open reviews", "team", teamName, "max_open_reviews", maxOpen)
+			return Team{}, err
+		}
+
+		team, err := s.teamRepo.GetByName(ctx, teamName)
+		if err != nil {
+			s.log.Warn("failed to get team", "team", teamName, "error", err)
+			return Team{}, err
+		}
+		team.MaxOpenReviews = maxOpen
+
+		updated, err := s.teamRepo.Update(ctx, team)
+		if err != nil {
+			s.log.Error("failed to update team", "team", teamName, "error", err)
+			return Team{}, err
+		}
+		return updated, nil
+	})
+}
+
+// SetTeamReviewersLimits задаёт min_reviewers и max_reviewers команды. Новое
+// значение max_reviewers не может быть меньше суммы квот ролей команды; уже
+// назначенные ревью не меняются.
+func (s *Service) SetTeamReviewersLimits(ctx context.Context, teamName string, minReviewers, maxReviewers int) (Team, error) {
+	return inTx(ctx, s, func(ctx context.Context) (Team, error) {
+		s.log.Info("SetTeamReviewersLimits called", "team", teamName, "min", minReviewers, "max", maxReviewers)
+
+		team, err := s.teamRepo.GetByName(ctx, teamName)
+		if err != nil {
+			s.log.Warn("failed to get team", "team", teamName, "error", err)
+			return Team{}, err
+		}
+		team.MinReviewers, team.MaxReviewers = minReviewers, maxReviewers
+		if err := team.ValidateReviewersLimits(); err != nil {
+			s.log.Warn("invalid reviewers limits", "team", teamName, "min", minReviewers, "max", maxReviewers)
+			return Team{}, err
+		}
+
+		quotas, err := s.teamRepo.ListRoleQuotas(ctx, teamName)
+		if err != nil {
+			s.log.Error("failed to list role quotas", "team", teamName, "error", err)
+			return Team{}, err
+		}
+		if err := validateRoleQuotas(team, quotas); err != nil {
+			s.log.Warn("role quotas exceed max reviewers", "team", teamName, "quotas", quotas, "max", maxReviewers)
+			return Team{}, err
+		}
+
+		updated, err := s.teamRepo.Update(ctx, team)
+		if err != nil {
+			s.log.Error("failed to update team", "team", teamName, "error", err)
+			return Team{}, err
+		}
+		return updated, nil
+	})
+}
+
+// SetTeamFallbacks задаёт упорядоченный список резервных команд, из которых
+// берутся ревьюверы, если команда не может их обеспечить сама. Пустой список
+// отключает резерв.
+func (s *Service) SetTeamFallbacks(ctx context.Context, teamName string, fallbacks []string) ([]string, error) {
+	return inTx(ctx, s, func(ctx context.Context) ([]string, error) {
+		s.log.Info("SetTeamFallbacks called", "team", teamName, "fallbacks", fallbacks)
+
+		seen := map[string]struct{}{teamName: {}}
+		for _, fb := range fallbacks {
+			if _, dup := seen[fb]; dup || fb == "" {
+				s.log.Warn("invalid fallback team", "team", teamName, "fallback", fb)
+				return nil, ErrInvalidFallbackTeams
+			}
+			seen[fb] = struct{}{}
+		}
+
+		if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+			s.log.Warn("failed to get team", "team", teamName, "error", err)
+			return nil, err
+		}
+		if err := s.teamRepo.SetFallbacks(ctx, teamName, fallbacks); err != nil {
+			s.log.Error("failed to set fallback teams", "team", teamName, "error", err)
+			return nil, err
+		}
+		return s.teamRepo.ListFallbacks(ctx, teamName)
+	})
+}
+
+func (s *Service) ListTeamFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	return s.teamRepo.ListFallbacks(ctx, teamName)
+}
+
+// AddTeamMember добавляет в существующую команду нового пользователя или
+// пользователя без команды.
+func (s *Service) AddTeamMember(ctx context.Context, teamName string, u User) (User, error) {
+	return inTx(ctx, s, func(ctx context.Context) (User, error) {
+		return s.addTeamMember(ctx, teamName, u)
+	})
+}
+
+func (s *Service) addTeamMember(ctx context.Context, teamName string, u User) (User, error) {
+	s.log.Info("AddTeamMember called", "team", teamName, "user_id", u.ID)
+
+	if err := validateMaxOpenReviews(u.MaxOpenReviews); err != nil {
+		s.log.Warn("invalid max open reviews", "user_id", u.ID, "max_open_reviews", u.MaxOpenReviews)
+		return User{}, err
+	}
+	if err := validateSeniority(u.Seniority); err != nil {
+		s.log.Warn("invalid seniority", "user_id", u.ID, "seniority", u.Seniority)
+		return User{}, err
+	}
+
+	if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+		s.log.Warn("failed to get team", "team", teamName, "error", err)
+		return User{}, err
+	}
+
+	u.Team = teamName
+	existing, err := s.userRepo.GetByID(ctx, u.ID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		s.log.Error("failed to get user by ID", "user_id", u.ID, "error", err)
+		return User{}, err
+	}
+	if err != nil {
+		return s.userRepo.Create(ctx, u)
+	}
+
+	if existing.Team != "" && existing.Team != teamName {
+		s.log.Warn("user already in another team", "user_id", u.ID, "team", existing.Team)
+		return User{}, ErrUserInAnotherTeam
+	}
+	if u.Name == "" {
+		u.Name = existing.Name
+	}
+	if u.MaxOpenReviews == 0 {
+		u.MaxOpenReviews = existing.MaxOpenReviews
+	}
+	if u.Seniority == "" {
+		u.Seniority = existing.Seniority
+	}
+	return s.userRepo.Update(ctx, u)
+}
+
+// RemoveTeamMember выводит пользователя из команды. Его открытые ревью
+// передаются кандидатам из команды автора PR; PR без кандидата остаются
+// за пользователем и перечисляются в отчёте.
+func (s *Service) RemoveTeamMember(ctx context.Context, teamName, userID string) (ReassignmentReport, error) {
+	return inTx(ctx, s, func(ctx context.Context) (ReassignmentReport, error) {
+		return s.removeTeamMember(ctx, teamName, userID)
+	})
+}
+
+func (s *Service) removeTeamMember(ctx context.Context, teamName, userID string) (ReassignmentReport, error) {
+	s.log.Info("RemoveTeamMember called", "team", teamName, "user_id", userID)
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.log.Warn("failed to get user", "user_id", userID, "error", err)
+		return ReassignmentReport{}, err
+	}
+	if u.Team != teamName {
+		s.log.Warn("user is not a member of team", "user_id", userID, "team", teamName, "user_team", u.Team)
+		return ReassignmentReport{}, ErrNotInTeam
+	}
+
+	u.Team = ""
+	if _, err := s.userRepo.Update(ctx, u); err != nil {
+		s.log.Error("failed to remove user from team", "user_id", userID, "error", err)
+		return ReassignmentReport{}, err
+	}
+
+	return s.redistributeReviews(ctx, userID, "removed from team "+teamName)
+}
+
+// RenameTeam переименовывает команду; участники и назначенные ревью не меняются.
+func (s *Service) RenameTeam(ctx context.Context, oldName, newName string) (Team, error) {
+	return inTx(ctx, s, func(ctx context.Context) (Team, error) {
+		s.log.Info("RenameTeam called", "team", oldName, "new_name", newName)
+		return s.teamRepo.Rename(ctx, oldName, newName)
+	})
+}
+
+// DeleteTeam удаляет команду без участников; иначе возвращает ErrTeamNotEmpty.
+func (s *Service) DeleteTeam(ctx context.Context, name string) error {
+	return s.txm.WithinTx(ctx, func(ctx context.Context) error {
+		s.log.Info("DeleteTeam called", "team", name)
+
+		members, err := s.userRepo.ListByTeam(ctx, name)
+		if err != nil {
+			s.log.Error("failed to list team members", "team", name, "error", err)
+			return err
+		}
+		if len(members) > 0 {
+			s.log.Warn("cannot delete team with members", "team", name, "members", len(members))
+			return ErrTeamNotEmpty
+		}
+
+		return s.teamRepo.Delete(ctx, name)
+	})
+}
+
+// DeactivateTeamUsers деактивирует участников команды одной транзакцией и
+// передаёт их OPEN-ревью кандидатам так же, как redistributeReviews. PR и
+// авторы читаются, а замены сохраняются одним запросом каждый; резервные
+// команды и правила подбора читаются по разу на команду авторов, статистика
+// кандидатов — по разу на просмотренную команду. Число запросов растёт с
+// числом затронутых команд, но не с числом PR и авторов.
+func (s *Service) DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string) (ReassignmentReport, error) {
+	return inTx(ctx, s, func(ctx context.Context) (ReassignmentReport, error) {
+		return s.deactivateTeamUsers(ctx, teamName, userIDs)
+	})
+}
+
+func (s *Service) deactivateTeamUsers(ctx context.Context, teamName string, userIDs []string) (ReassignmentReport, error) {
+	s.log.Info("DeactivateTeamUsers called", "team", teamName, "users_count", len(userIDs))
+
+	if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+		s.log.Warn("failed to get team", "team", teamName, "error", err)
+		return ReassignmentReport{}, err
+	}
+
+	members, err := s.userRepo.ListByTeam(ctx, teamName)
+	if err != nil {
+		s.log.Error("failed to list team members", "team", teamName, "error", err)
+		return ReassignmentReport{}, err
+	}
+	inTeam := make(map[string]struct{}, len(members))
+	for _, u := range members {
+		inTeam[u.ID] = struct{}{}
+	}
+
+	deactivated := make(map[string]struct{}, len(userIDs))
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := inTeam[id]; !ok {
+			s.log.Warn("user is not a member of team", "user_id", id, "team", teamName)
+			return ReassignmentReport{}, ErrNotInTeam
+		}
+		if _, dup := deactivated[id]; dup {
+			continue
+		}
+		deactivated[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	if err := s.userRepo.SetActive(ctx, ids, false); err != nil {
+		s.log.Error("failed to deactivate users", "team", teamName, "error", err)
+		return ReassignmentReport{}, err
+	}
+
+	prs, err := s.prRepo.ListOpenByReviewers(ctx, ids)
+	if err != nil {
+		s.log.Error("failed to list open PRs of deactivated users", "team", teamName, "error", err)
+		return ReassignmentReport{}, err
+	}
+
+	authorIDs := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		authorIDs = append(authorIDs, pr.AuthorID)
+	}
+	authorList, err := s.userRepo.ListByIDs(ctx, authorIDs)
+	if err != nil {
+		s.log.Error("failed to list PR authors", "team", teamName, "error", err)
+		return ReassignmentReport{}, err
+	}
+	authors := make(map[string]User, len(authorList))
+	for _, u := range authorList {
+		authors[u.ID] = u
+	}
+
+	// Замена ищется так же, как при переназначении одного ревью: в команде
+	// автора PR, затем в её резервных командах, с правилами подбора автора.
+	// Команды плана и правила читаются один раз на команду авторов, правила
+	// конкретного автора отбираются из них в памяти. Статистика каждой команды
+	// читается один раз после деактивации; нагрузка и очередь выбранных
+	// кандидатов учитываются локально, чтобы least_loaded и round_robin
+	// распределяли ревью, а лимиты OPEN-ревью соблюдались в пределах всей
+	// операции.
+	var (
+		seq       int64
+		stats     = map[string][]ReviewerStats{}
+		teamPlans = map[string]replacementPlan{}
+		teamRules = map[string][]ReviewerRule{}
+		plans     = map[string]replacementPlan{}
+	)
+	load := func(ctx context.Context, team string) ([]ReviewerStats, error) {
+		if st, ok := stats[team]; ok {
+			return st, nil
+		}
+		st, err := s.availableReviewerStats(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		// Новые назначения получают метки позже всех сохранённых, чтобы
+		// round_robin продолжал очередь и внутри операции.
+		for _, c := range st {
+			seq = max(seq, c.LastAssigned)
+		}
+		stats[team] = st
+		return st, nil
+	}
+
+	cause := "bulk deactivation in team " + teamName
+
+	var (
+		report ReassignmentReport
+		swaps  []ReviewerSwap
+		events []PREvent
+	)
+	for _, pr := range prs {
+		author, ok := authors[pr.AuthorID]
+		if !ok {
+			s.log.Error("PR author not found", "pr_id", pr.ID, "author_id", pr.AuthorID)
+			return ReassignmentReport{}, ErrNotFound
+		}
+		plan, ok := plans[author.ID]
+		if !ok && author.Team != "" {
+			tp, ok := teamPlans[author.Team]
+			if !ok {
+				if tp, err = s.planTeams(ctx, author.Team, author.Team); err != nil {
+					return ReassignmentReport{}, err
+				}
+				if teamRules[author.Team], err = s.teamRepo.ListReviewerRules(ctx, author.Team); err != nil {
+					s.log.Error("failed to list reviewer rules", "error", err, "team", author.Team)
+					return ReassignmentReport{}, err
+				}
+				teamPlans[author.Team] = tp
+			}
+			plan = tp
+			plan.rules = newAuthorRules(author.ID, teamRules[author.Team])
+			plans[author.ID] = plan
+		}
+
+		for _, oldID := range pr.ReviewerIDs {
+			if _, ok := deactivated[oldID]; !ok {
+				continue
+			}
+
+			var (
+				selection Selection
+				team      string
+			)
+			if author.Team == "" {
+				err = ErrNoCandidate
+			} else {
+				selection, team, err = s.replaceFrom(ctx, pr, plan, load)
+			}
+			if err == ErrNoCandidate || err == ErrAllReviewersAtCapacity {
+				report.Failed = append(report.Failed, ReassignmentFailure{
+					PRID:       pr.ID,
+					ReviewerID: oldID,
+					Reason:     err.Error(),
+				})
+				continue
+			}
+			if err != nil {
+				return ReassignmentReport{}, err
+			}
+
+			newID := selection.ReviewerIDs[0]
+			pr.ReviewerIDs = replaceID(pr.ReviewerIDs, oldID, newID)
+			for i, st := range stats[team] {
+				if st.UserID == newID {
+					seq++
+					stats[team][i].AssignedOpenPRs++
+					stats[team][i].LastAssigned = seq
+					break
+				}
+			}
+
+			swaps = append(swaps, ReviewerSwap{
+				PRID:            pr.ID,
+				Version:         pr.Version,
+				OldReviewerID:   oldID,
+				NewReviewerID:   newID,
+				NewReviewerTeam: team,
+			})
+			ev := newEvent(ctx, pr.ID, EventReviewerReassigned)
+			ev.OldReviewerID = oldID
+			ev.NewReviewerID = newID
+			ev.Details = cause + "; " + selection.Reason
+			events = append(events, ev)
+
+			report.Reassigned = append(report.Reassigned, Reassignment{
+				PRID:          pr.ID,
+				OldReviewerID: oldID,
+				NewReviewerID: newID,
+			})
+		}
+	}
+
+	if len(swaps) > 0 {
+		if err := s.prRepo.ReplaceReviewers(ctx, swaps, events...); err != nil {
+			s.log.Error("failed to replace reviewers", "team", teamName, "error", err)
+			return ReassignmentReport{}, err
+		}
+	}
+
+	s.log.Info("team users deactivated", "team", teamName, "users", len(ids), "prs", len(prs),
+		"reassigned", len(report.Reassigned), "failed", len(report.Failed))
+	return report, nil
+}
@@ -2,16 +2,186 @@ package review
 
 import (
 	"context"
+	"slices"
+	"time"
 )
 
 func (s *Service) GetAssignedForUser(ctx context.Context, userID string) ([]PullRequest, error) {
 	return s.prRepo.ListAssignedTo(ctx, userID)
 }
 
-func (s *Service) UpdateUser(ctx context.Context, u User) (User, error) {
-	return s.userRepo.Update(ctx, u)
+// SetUserActive меняет флаг активности пользователя. При деактивации с
+// reassign=true его открытые ревью в той же транзакции передаются другим
+// активным кандидатам; без reassign назначения не меняются.
+func (s *Service) SetUserActive(ctx context.Context, userID string, active, reassign bool) (User, ReassignmentReport, error) {
+	var report ReassignmentReport
+	u, err := inTx(ctx, s, func(ctx context.Context) (User, error) {
+		u, r, err := s.setUserActive(ctx, userID, active, reassign)
+		report = r
+		return u, err
+	})
+	if err != nil {
+		return User{}, ReassignmentReport{}, err
+	}
+	return u, report, nil
+}
+
+func (s *Service) setUserActive(ctx context.Context, userID string, active, reassign bool) (User, ReassignmentReport, error) {
+	s.log.Info("SetUserActive called", "user_id", userID, "is_active", active, "reassign", reassign)
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.log.Warn("failed to get user", "user_id", userID, "error", err)
+		return User{}, ReassignmentReport{}, err
+	}
+
+	u.IsActive = active
+	updated, err := s.userRepo.Update(ctx, u)
+	if err != nil {
+		s.log.Error("failed to update user active status", "user_id", userID, "error", err)
+		return User{}, ReassignmentReport{}, err
+	}
+
+	if active || !reassign {
+		return updated, ReassignmentReport{}, nil
+	}
+
+	report, err := s.redistributeReviews(ctx, userID, "reviewer deactivated")
+	if err != nil {
+		return User{}, ReassignmentReport{}, err
+	}
+	return updated, report, nil
+}
+
+// SetUserMaxOpenReviews задаёт личный лимит OPEN-ревью пользователя;
+// 0 возвращает лимит команды.
+func (s *Service) SetUserMaxOpenReviews(ctx context.Context, userID string, maxOpen int) (User, error) {
+	return inTx(ctx, s, func(ctx context.Context) (User, error) {
+		s.log.Info("SetUserMaxOpenReviews called", "user_id", userID, "max_open_reviews", maxOpen)
+
+		if err := validateMaxOpenReviews(maxOpen); err != nil {
+			s.log.Warn("invalid max open reviews", "user_id", userID, "max_open_reviews", maxOpen)
+			return User{}, err
+		}
+
+		u, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			s.log.Warn("failed to get user", "user_id", userID, "error", err)
+			return User{}, err
+		}
+		u.MaxOpenReviews = maxOpen
+
+		updated, err := s.userRepo.Update(ctx, u)
+		if err != nil {
+			s.log.Error("failed to update user max open reviews", "user_id", userID, "error", err)
+			return User{}, err
+		}
+		return updated, nil
+	})
+}
+
+// SetUserTags заменяет теги навыков пользователя; пустой список снимает все.
+func (s *Service) SetUserTags(ctx context.Context, userID string, tags []string) (User, error) {
+	return inTx(ctx, s, func(ctx context.Context) (User, error) {
+		s.log.Info("SetUserTags called", "user_id", userID, "tags", tags)
+
+		normalized, err := normalizeTags(tags)
+		if err != nil {
+			s.log.Warn("invalid user tags", "user_id", userID, "tags", tags)
+			return User{}, err
+		}
+		slices.Sort(normalized)
+
+		if err := s.userRepo.SetTags(ctx, userID, normalized); err != nil {
+			s.log.Error("failed to set user tags", "user_id", userID, "error", err)
+			return User{}, err
+		}
+		return s.userRepo.GetByID(ctx, userID)
+	})
 }
 
 func (s *Service) GetUserByID(ctx context.Context, id string) (User, error) {
 	return s.userRepo.GetByID(ctx, id)
 }
+
+// MoveUserToTeam переводит пользователя в другую команду и в той же
+// транзакции передаёт его открытые ревью кандидатам из команды автора PR.
+func (s *Service) MoveUserToTeam(ctx context.Context, userID, teamName string) (User, ReassignmentReport, error) {
+	var report ReassignmentReport
+	u, err := inTx(ctx, s, func(ctx context.Context) (User, error) {
+		u, r, err := s.moveUserToTeam(ctx, userID, teamName)
+		report = r
+		return u, err
+	})
+	if err != nil {
+		return User{}, ReassignmentReport{}, err
+	}
+	return u, report, nil
+}
+
+func (s *Service) moveUserToTeam(ctx context.Context, userID, teamName string) (User, ReassignmentReport, error) {
+	s.log.Info("MoveUserToTeam called", "user_id", userID, "team", teamName)
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.log.Warn("failed to get user", "user_id", userID, "error", err)
+		return User{}, ReassignmentReport{}, err
+	}
+	if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+		s.log.Warn("failed to get team", "team", teamName, "error", err)
+		return User{}, ReassignmentReport{}, err
+	}
+	if u.Team == teamName {
+		s.log.Info("user already in team", "user_id", userID, "team", teamName)
+		return u, ReassignmentReport{}, nil
+	}
+
+	from := u.Team
+	u.Team = teamName
+	updated, err := s.userRepo.Update(ctx, u)
+	if err != nil {
+		s.log.Error("failed to move user", "user_id", userID, "error", err)
+		return User{}, ReassignmentReport{}, err
+	}
+
+	cause := "moved to team " + teamName
+	if from != "" {
+		cause = "moved from team " + from + " to " + teamName
+	}
+	report, err := s.redistributeReviews(ctx, userID, cause)
+	if err != nil {
+		return User{}, ReassignmentReport{}, err
+	}
+
+	s.log.Info("user moved successfully", "user_id", userID, "from", from, "to", teamName)
+	return updated, report, nil
+}
+
+// AddAbsence регистрирует отсутствие пользователя. Уже назначенные ревью
+// не переназначаются: отсутствие влияет только на новые назначения.
+func (s *Service) AddAbsence(ctx context.Context, a Absence) (Absence, error) {
+	s.log.Info("AddAbsence called", "user_id", a.UserID, "starts_at", a.StartsAt, "ends_at", a.EndsAt)
+
+	if err := a.Validate(); err != nil {
+		s.log.Warn("invalid absence", "user_id", a.UserID, "starts_at", a.StartsAt, "ends_at", a.EndsAt)
+		return Absence{}, err
+	}
+	a.StartsAt = a.StartsAt.UTC()
+	a.EndsAt = a.EndsAt.UTC()
+
+	created, err := s.userRepo.AddAbsence(ctx, a)
+	if err != nil {
+		s.log.Error("failed to add absence", "user_id", a.UserID, "error", err)
+		return Absence{}, err
+	}
+	return created, nil
+}
+
+// ListTeamAbsences возвращает текущие и предстоящие отсутствия участников команды.
+func (s *Service) ListTeamAbsences(ctx context.Context, teamName string) ([]Absence, error) {
+	if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+		s.log.Warn("failed to get team", "team", teamName, "error", err)
+		return nil, err
+	}
+	return s.userRepo.ListTeamAbsences(ctx, teamName, time.Now().UTC())
+}
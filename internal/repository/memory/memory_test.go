@@ -0,0 +1,26 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/zapevnik/pr-review-service/internal/repository/memory"
+	"github.com/zapevnik/pr-review-service/internal/repository/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, newRepos)
+}
+
+func BenchmarkDeactivateTeamUsers(b *testing.B) {
+	repotest.BenchmarkDeactivateTeamUsers(b, newRepos)
+}
+
+func newRepos(testing.TB) repotest.Repos {
+	st := memory.NewStore()
+	return repotest.Repos{
+		PRs:   memory.NewPRRepo(st),
+		Users: memory.NewUserRepo(st),
+		Teams: memory.NewTeamRepo(st),
+		Tx:    memory.NewTxManager(st),
+	}
+}
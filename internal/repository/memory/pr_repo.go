@@ -0,0 +1,312 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+)
+
+type PRRepo struct {
+	st *Store
+}
+
+func NewPRRepo(st *Store) *PRRepo {
+	return &PRRepo{st: st}
+}
+
+func (r *PRRepo) Create(ctx context.Context, pr review.PullRequest, events ...review.PREvent) (review.PullRequest, error) {
+	defer r.st.lock(ctx)()
+
+	if _, exists := r.st.prs[pr.ID]; exists {
+		return review.PullRequest{}, review.ErrPRExists
+	}
+	if _, ok := r.st.users[pr.AuthorID]; !ok {
+		return review.PullRequest{}, review.ErrNotFound
+	}
+	for _, id := range pr.ReviewerIDs {
+		if _, ok := r.st.users[id]; !ok || !r.st.teamExists(pr.ReviewerTeams[id]) {
+			return review.PullRequest{}, review.ErrNotFound
+		}
+	}
+
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = time.Now().UTC()
+	}
+	pr.Version = 1
+	pr.MergedAt, pr.ClosedAt = nil, nil
+	pr.MergeForced = false
+	pr.ClosedFrom = ""
+	pr.UnmatchedLabels = nil
+
+	put(r.st, r.st.prs, pr.ID, clonePR(pr))
+	r.st.appendEvents(events)
+
+	return r.st.getPR(pr.ID)
+}
+
+func (r *PRRepo) Update(ctx context.Context, pr review.PullRequest, events ...review.PREvent) (review.PullRequest, error) {
+	defer r.st.lock(ctx)()
+
+	cur, ok := r.st.prs[pr.ID]
+	if !ok {
+		return review.PullRequest{}, review.ErrNotFound
+	}
+	if cur.Version != pr.Version {
+		return review.PullRequest{}, review.ErrConflict
+	}
+	for _, id := range pr.ReviewerIDs {
+		if _, ok := r.st.users[id]; !ok || !r.st.teamExists(pr.ReviewerTeams[id]) {
+			return review.PullRequest{}, review.ErrNotFound
+		}
+	}
+
+	// Как и в SQL-реализации, меняются только изменяемые поля PR.
+	cur.Title = pr.Title
+	cur.Status = pr.Status
+	cur.MergedAt = pr.MergedAt
+	cur.ClosedAt = pr.ClosedAt
+	cur.MergeForced = pr.MergeForced
+	cur.ClosedFrom = pr.ClosedFrom
+	cur.ReviewerIDs = pr.ReviewerIDs
+	cur.ReviewerTeams = pr.ReviewerTeams
+	cur.Version++
+
+	put(r.st, r.st.prs, pr.ID, clonePR(cur))
+	r.st.appendEvents(events)
+
+	return r.st.getPR(pr.ID)
+}
+
+func (r *PRRepo) GetByID(ctx context.Context, id string) (review.PullRequest, error) {
+	defer r.st.lock(ctx)()
+
+	return r.st.getPR(id)
+}
+
+func (r *PRRepo) ListAssignedTo(ctx context.Context, userID string) ([]review.PullRequest, error) {
+	defer r.st.lock(ctx)()
+
+	var result []review.PullRequest
+	for _, pr := range r.st.prs {
+		for _, id := range pr.ReviewerIDs {
+			if id == userID {
+				full, err := r.st.getPR(pr.ID)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, full)
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *PRRepo) AddReview(ctx context.Context, rv review.Review, version int64, events ...review.PREvent) (review.Review, error) {
+	defer r.st.lock(ctx)()
+
+	pr, ok := r.st.prs[rv.PRID]
+	if !ok {
+		return review.Review{}, review.ErrNotFound
+	}
+	if pr.Version != version {
+		return review.Review{}, review.ErrConflict
+	}
+	if _, ok := r.st.users[rv.ReviewerID]; !ok {
+		return review.Review{}, review.ErrNotFound
+	}
+	if rv.SubmittedAt.IsZero() {
+		rv.SubmittedAt = time.Now().UTC()
+	}
+
+	r.st.nextReviewID++
+	rv.ID = r.st.nextReviewID
+	put(r.st, r.st.reviews, rv.PRID, append(r.st.reviews[rv.PRID], rv))
+
+	pr.Version++
+	put(r.st, r.st.prs, rv.PRID, pr)
+	r.st.appendEvents(events)
+
+	return rv, nil
+}
+
+func (r *PRRepo) ListEvents(ctx context.Context, prID string) ([]review.PREvent, error) {
+	defer r.st.lock(ctx)()
+
+	return append([]review.PREvent(nil), r.st.events[prID]...), nil
+}
+
+func (r *PRRepo) ListReviewerStats(ctx context.Context, teamName string) ([]review.ReviewerStats, error) {
+	defer r.st.lock(ctx)()
+
+	load := map[string]int{}
+	for _, pr := range r.st.prs {
+		if pr.Status != review.StatusOpen {
+			continue
+		}
+		for _, id := range pr.ReviewerIDs {
+			load[id]++
+		}
+	}
+
+	last := map[string]int64{}
+	for _, events := range r.st.events {
+		for _, ev := range events {
+			if ev.Type == review.EventReviewerAssigned || ev.Type == review.EventReviewerReassigned {
+				last[ev.NewReviewerID] = max(last[ev.NewReviewerID], ev.ID)
+			}
+		}
+	}
+
+	var result []review.ReviewerStats
+	for _, u := range r.st.usersByTeam(teamName, true) {
+		maxOpen := u.MaxOpenReviews
+		if maxOpen == 0 {
+			maxOpen = r.st.teams[u.Team].MaxOpenReviews
+		}
+		result = append(result, review.ReviewerStats{
+			UserID:          u.ID,
+			Username:        u.Name,
+			TeamName:        u.Team,
+			AssignedOpenPRs: load[u.ID],
+			MaxOpenReviews:  maxOpen,
+			Tags:            u.Tags,
+			Seniority:       u.Seniority,
+			LastAssigned:    last[u.ID],
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].AssignedOpenPRs < result[j].AssignedOpenPRs
+	})
+	return result, nil
+}
+
+func (s *Store) getPR(id string) (review.PullRequest, error) {
+	pr, ok := s.prs[id]
+	if !ok {
+		return review.PullRequest{}, review.ErrNotFound
+	}
+	pr = clonePR(pr)
+	if len(pr.ReviewerIDs) == 0 {
+		pr.ReviewerIDs = nil
+	}
+	pr.Reviews = append([]review.Review(nil), s.reviews[id]...)
+	return pr, nil
+}
+
+func (s *Store) appendEvents(events []review.PREvent) {
+	for _, ev := range events {
+		if ev.CreatedAt.IsZero() {
+			ev.CreatedAt = time.Now().UTC()
+		}
+		s.nextEventID++
+		ev.ID = s.nextEventID
+		put(s, s.events, ev.PRID, append(s.events[ev.PRID], ev))
+	}
+}
+
+func (r *PRRepo) ListOpenByReviewers(ctx context.Context, reviewerIDs []string) ([]review.PullRequest, error) {
+	defer r.st.lock(ctx)()
+
+	wanted := make(map[string]struct{}, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		wanted[id] = struct{}{}
+	}
+
+	var result []review.PullRequest
+	for _, pr := range r.st.prs {
+		if pr.Status != review.StatusOpen {
+			continue
+		}
+		for _, id := range pr.ReviewerIDs {
+			if _, ok := wanted[id]; ok {
+				cp := clonePR(pr)
+				cp.ChangedFiles, cp.Labels = nil, nil
+				result = append(result, cp)
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *PRRepo) ReplaceReviewers(ctx context.Context, swaps []review.ReviewerSwap, events ...review.PREvent) error {
+	defer r.st.lock(ctx)()
+
+	// Сначала проверяются все PR, чтобы при конфликте ничего не изменить.
+	updated := map[string]review.PullRequest{}
+	for _, sw := range swaps {
+		pr, ok := updated[sw.PRID]
+		if !ok {
+			cur, exists := r.st.prs[sw.PRID]
+			if !exists || cur.Version != sw.Version {
+				return review.ErrConflict
+			}
+			pr = clonePR(cur)
+			pr.Version++
+		}
+		if _, ok := r.st.users[sw.NewReviewerID]; !ok || !r.st.teamExists(sw.NewReviewerTeam) {
+			return review.ErrNotFound
+		}
+		for i, id := range pr.ReviewerIDs {
+			if id == sw.OldReviewerID {
+				pr.ReviewerIDs[i] = sw.NewReviewerID
+				break
+			}
+		}
+		if pr.ReviewerTeams == nil {
+			pr.ReviewerTeams = map[string]string{}
+		}
+		pr.ReviewerTeams[sw.NewReviewerID] = sw.NewReviewerTeam
+		updated[sw.PRID] = pr
+	}
+
+	for id, pr := range updated {
+		put(r.st, r.st.prs, id, clonePR(pr))
+	}
+	r.st.appendEvents(events)
+	return nil
+}
+
+func (r *PRRepo) SetOwnershipRules(ctx context.Context, repository string, rules []review.OwnershipRule) error {
+	defer r.st.lock(ctx)()
+
+	for _, rule := range rules {
+		for _, id := range rule.Owners {
+			if _, ok := r.st.users[id]; !ok {
+				return review.ErrNotFound
+			}
+		}
+	}
+
+	if len(rules) == 0 {
+		del(r.st, r.st.ownership, repository)
+		return nil
+	}
+	put(r.st, r.st.ownership, repository, cloneRules(rules))
+	return nil
+}
+
+func (r *PRRepo) ListOwnershipRules(ctx context.Context, repository string) ([]review.OwnershipRule, error) {
+	defer r.st.lock(ctx)()
+
+	rules, ok := r.st.ownership[repository]
+	if !ok {
+		return nil, nil
+	}
+	return cloneRules(rules), nil
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+)
+
+// Store — in-memory хранилище для тестов и локального запуска без БД.
+// Все операции сериализуются одним мьютексом; транзакция из WithinTx
+// держит его до конца, записывает прежние значения изменённых ключей в
+// журнал и при ошибке восстанавливает только их.
+type Store struct {
+	mu sync.Mutex
+
+	teams   map[string]review.Team
+	users   map[string]review.User
+	prs     map[string]review.PullRequest
+	reviews map[string][]review.Review
+	events  map[string][]review.PREvent
+
+	absences  []review.Absence
+	fallbacks map[string][]string
+	quotas    map[string][]review.RoleQuota
+	rules     map[string][]review.ReviewerRule
+	ownership map[string][]review.OwnershipRule
+
+	nextReviewID  int64
+	nextEventID   int64
+	nextAbsenceID int64
+
+	// inTx и undo — журнал отката текущей транзакции.
+	inTx bool
+	undo []func()
+}
+
+func NewStore() *Store {
+	return &Store{
+		teams:   map[string]review.Team{},
+		users:   map[string]review.User{},
+		prs:     map[string]review.PullRequest{},
+		reviews: map[string][]review.Review{},
+		events:  map[string][]review.PREvent{},
+
+		fallbacks: map[string][]string{},
+		quotas:    map[string][]review.RoleQuota{},
+		rules:     map[string][]review.ReviewerRule{},
+		ownership: map[string][]review.OwnershipRule{},
+	}
+}
+
+type txKey struct{}
+
+// lock захватывает хранилище, если вызов не выполняется внутри WithinTx.
+func (s *Store) lock(ctx context.Context) func() {
+	if st, ok := ctx.Value(txKey{}).(*Store); ok && st == s {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// remember запоминает прежнее значение m[k], чтобы откатить его при ошибке
+// транзакции. Вне WithinTx ничего не делает.
+func remember[K comparable, V any](s *Store, m map[K]V, k K) {
+	if !s.inTx {
+		return
+	}
+	old, ok := m[k]
+	s.undo = append(s.undo, func() {
+		if ok {
+			m[k] = old
+		} else {
+			delete(m, k)
+		}
+	})
+}
+
+// put записывает m[k] = v с учётом отката транзакции.
+func put[K comparable, V any](s *Store, m map[K]V, k K, v V) {
+	remember(s, m, k)
+	m[k] = v
+}
+
+// del удаляет m[k] с учётом отката транзакции.
+func del[K comparable, V any](s *Store, m map[K]V, k K) {
+	remember(s, m, k)
+	delete(m, k)
+}
+
+// begin начинает журнал отката. Списки и счётчики только растут, поэтому
+// для них достаточно запомнить длину и значения на начало транзакции.
+func (s *Store) begin() {
+	s.inTx = true
+	s.undo = s.undo[:0]
+
+	absences := s.absences
+	nextReviewID, nextEventID, nextAbsenceID := s.nextReviewID, s.nextEventID, s.nextAbsenceID
+	s.undo = append(s.undo, func() {
+		s.absences = absences
+		s.nextReviewID, s.nextEventID, s.nextAbsenceID = nextReviewID, nextEventID, nextAbsenceID
+	})
+}
+
+// end завершает транзакцию; при rollback изменения отменяются в обратном
+// порядке.
+func (s *Store) end(rollback bool) {
+	if rollback {
+		for i := len(s.undo) - 1; i >= 0; i-- {
+			s.undo[i]()
+		}
+	}
+	clear(s.undo)
+	s.undo = s.undo[:0]
+	s.inTx = false
+}
+
+// clonePR копирует PR; в ReviewerTeams остаются только текущие ревьюверы
+// с известной командой, как при хранении команды в строке назначения.
+func clonePR(pr review.PullRequest) review.PullRequest {
+	var teams map[string]string
+	for _, id := range pr.ReviewerIDs {
+		if t := pr.ReviewerTeams[id]; t != "" {
+			if teams == nil {
+				teams = map[string]string{}
+			}
+			teams[id] = t
+		}
+	}
+	pr.ReviewerIDs = append([]string(nil), pr.ReviewerIDs...)
+	pr.ReviewerTeams = teams
+	pr.ChangedFiles = append([]string(nil), pr.ChangedFiles...)
+	pr.Labels = append([]string(nil), pr.Labels...)
+	pr.Reviews = nil
+	return pr
+}
+
+func cloneRules(rules []review.OwnershipRule) []review.OwnershipRule {
+	out := make([]review.OwnershipRule, 0, len(rules))
+	for _, rule := range rules {
+		rule.Owners = append([]string(nil), rule.Owners...)
+		out = append(out, rule)
+	}
+	return out
+}
+
+// TxManager реализует review.TxManager для Store.
+type TxManager struct {
+	st *Store
+}
+
+func NewTxManager(st *Store) *TxManager {
+	return &TxManager{st: st}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if st, ok := ctx.Value(txKey{}).(*Store); ok && st == m.st {
+		return fn(ctx)
+	}
+
+	m.st.mu.Lock()
+	defer m.st.mu.Unlock()
+
+	m.st.begin()
+	committed := false
+	defer func() { m.st.end(!committed) }()
+
+	if err := fn(context.WithValue(ctx, txKey{}, m.st)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+)
+
+type TeamRepo struct {
+	st *Store
+}
+
+func NewTeamRepo(st *Store) *TeamRepo {
+	return &TeamRepo{st: st}
+}
+
+func (r *TeamRepo) Create(ctx context.Context, team review.Team) (review.Team, error) {
+	defer r.st.lock(ctx)()
+
+	if _, exists := r.st.teams[team.Name]; exists {
+		return review.Team{}, review.ErrTeamExists
+	}
+	put(r.st, r.st.teams, team.Name, team)
+	return team, nil
+}
+
+func (r *TeamRepo) GetByName(ctx context.Context, name string) (review.Team, error) {
+	defer r.st.lock(ctx)()
+
+	t, ok := r.st.teams[name]
+	if !ok {
+		return review.Team{}, review.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *TeamRepo) Update(ctx context.Context, team review.Team) (review.Team, error) {
+	defer r.st.lock(ctx)()
+
+	if _, ok := r.st.teams[team.Name]; !ok {
+		return review.Team{}, review.ErrNotFound
+	}
+	put(r.st, r.st.teams, team.Name, team)
+	return team, nil
+}
+
+func (r *TeamRepo) Rename(ctx context.Context, oldName, newName string) (review.Team, error) {
+	defer r.st.lock(ctx)()
+
+	t, ok := r.st.teams[oldName]
+	if !ok {
+		return review.Team{}, review.ErrNotFound
+	}
+	if oldName == newName {
+		return t, nil
+	}
+	if _, exists := r.st.teams[newName]; exists {
+		return review.Team{}, review.ErrTeamExists
+	}
+
+	del(r.st, r.st.teams, oldName)
+	t.Name = newName
+	put(r.st, r.st.teams, newName, t)
+	for id, u := range r.st.users {
+		if u.Team == oldName {
+			u.Team = newName
+			put(r.st, r.st.users, id, u)
+		}
+	}
+	r.st.replaceTeamRefs(oldName, newName)
+	return t, nil
+}
+
+func (r *TeamRepo) Delete(ctx context.Context, name string) error {
+	defer r.st.lock(ctx)()
+
+	if _, ok := r.st.teams[name]; !ok {
+		return review.ErrNotFound
+	}
+	for _, u := range r.st.users {
+		if u.Team == name {
+			return review.ErrTeamNotEmpty
+		}
+	}
+	del(r.st, r.st.teams, name)
+	r.st.replaceTeamRefs(name, "")
+	return nil
+}
+
+func (r *TeamRepo) SetFallbacks(ctx context.Context, teamName string, fallbacks []string) error {
+	defer r.st.lock(ctx)()
+
+	if _, ok := r.st.teams[teamName]; !ok {
+		return review.ErrNotFound
+	}
+	for _, fb := range fallbacks {
+		if _, ok := r.st.teams[fb]; !ok {
+			return review.ErrNotFound
+		}
+	}
+	if len(fallbacks) == 0 {
+		del(r.st, r.st.fallbacks, teamName)
+		return nil
+	}
+	put(r.st, r.st.fallbacks, teamName, append([]string(nil), fallbacks...))
+	return nil
+}
+
+func (r *TeamRepo) ListFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	defer r.st.lock(ctx)()
+
+	return append([]string(nil), r.st.fallbacks[teamName]...), nil
+}
+
+func (r *TeamRepo) SetRoleQuotas(ctx context.Context, teamName string, quotas []review.RoleQuota) error {
+	defer r.st.lock(ctx)()
+
+	if _, ok := r.st.teams[teamName]; !ok {
+		return review.ErrNotFound
+	}
+	if len(quotas) == 0 {
+		del(r.st, r.st.quotas, teamName)
+		return nil
+	}
+	put(r.st, r.st.quotas, teamName, append([]review.RoleQuota(nil), quotas...))
+	return nil
+}
+
+func (r *TeamRepo) ListRoleQuotas(ctx context.Context, teamName string) ([]review.RoleQuota, error) {
+	defer r.st.lock(ctx)()
+
+	return append([]review.RoleQuota(nil), r.st.quotas[teamName]...), nil
+}
+
+func (r *TeamRepo) SetReviewerRules(ctx context.Context, teamName string, rules []review.ReviewerRule) error {
+	defer r.st.lock(ctx)()
+
+	if _, ok := r.st.teams[teamName]; !ok {
+		return review.ErrNotFound
+	}
+	for _, rule := range rules {
+		if _, ok := r.st.users[rule.AuthorID]; !ok {
+			return review.ErrNotFound
+		}
+		if _, ok := r.st.users[rule.ReviewerID]; !ok {
+			return review.ErrNotFound
+		}
+	}
+	if len(rules) == 0 {
+		del(r.st, r.st.rules, teamName)
+		return nil
+	}
+	put(r.st, r.st.rules, teamName, append([]review.ReviewerRule(nil), rules...))
+	return nil
+}
+
+func (r *TeamRepo) ListReviewerRules(ctx context.Context, teamName string) ([]review.ReviewerRule, error) {
+	defer r.st.lock(ctx)()
+
+	return append([]review.ReviewerRule(nil), r.st.rules[teamName]...), nil
+}
+
+// replaceTeamRefs переносит ссылки на команду oldName в списках резерва,
+// квотах ролей, правилах подбора и командах ревьюверов на newName; пустое
+// newName удаляет ссылки, как каскад внешних ключей в SQL-хранилищах.
+func (s *Store) replaceTeamRefs(oldName, newName string) {
+	if q, ok := s.quotas[oldName]; ok {
+		del(s, s.quotas, oldName)
+		if newName != "" {
+			put(s, s.quotas, newName, q)
+		}
+	}
+	if rules, ok := s.rules[oldName]; ok {
+		del(s, s.rules, oldName)
+		if newName != "" {
+			put(s, s.rules, newName, rules)
+		}
+	}
+	if fbs, ok := s.fallbacks[oldName]; ok {
+		del(s, s.fallbacks, oldName)
+		if newName != "" {
+			put(s, s.fallbacks, newName, fbs)
+		}
+	}
+	for team, fbs := range s.fallbacks {
+		if !slices.Contains(fbs, oldName) {
+			continue
+		}
+		// Новый срез: прежний может храниться в журнале отката.
+		kept := make([]string, 0, len(fbs))
+		for _, fb := range fbs {
+			switch {
+			case fb != oldName:
+				kept = append(kept, fb)
+			case newName != "":
+				kept = append(kept, newName)
+			}
+		}
+		if len(kept) == 0 {
+			del(s, s.fallbacks, team)
+		} else {
+			put(s, s.fallbacks, team, kept)
+		}
+	}
+
+	for id, pr := range s.prs {
+		var teams map[string]string
+		for rid, t := range pr.ReviewerTeams {
+			if t != oldName {
+				continue
+			}
+			if teams == nil {
+				// Копия: прежний PR может храниться в журнале отката.
+				teams = maps.Clone(pr.ReviewerTeams)
+			}
+			if newName == "" {
+				delete(teams, rid)
+			} else {
+				teams[rid] = newName
+			}
+		}
+		if teams != nil {
+			pr.ReviewerTeams = teams
+			put(s, s.prs, id, clonePR(pr))
+		}
+	}
+}
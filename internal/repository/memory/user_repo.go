@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+)
+
+type UserRepo struct {
+	st *Store
+}
+
+func NewUserRepo(st *Store) *UserRepo {
+	return &UserRepo{st: st}
+}
+
+func (r *UserRepo) Create(ctx context.Context, u review.User) (review.User, error) {
+	defer r.st.lock(ctx)()
+
+	if _, exists := r.st.users[u.ID]; exists {
+		return review.User{}, fmt.Errorf("user %q already exists", u.ID)
+	}
+	if !r.st.teamExists(u.Team) {
+		return review.User{}, review.ErrNotFound
+	}
+	u.Tags = nil
+	put(r.st, r.st.users, u.ID, u)
+	return u, nil
+}
+
+func (r *UserRepo) Update(ctx context.Context, u review.User) (review.User, error) {
+	defer r.st.lock(ctx)()
+
+	cur, exists := r.st.users[u.ID]
+	if !exists {
+		return review.User{}, review.ErrNotFound
+	}
+	if !r.st.teamExists(u.Team) {
+		return review.User{}, review.ErrNotFound
+	}
+	u.Tags = cur.Tags
+	put(r.st, r.st.users, u.ID, u)
+	return u, nil
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, userID string) (review.User, error) {
+	defer r.st.lock(ctx)()
+
+	u, ok := r.st.users[userID]
+	if !ok {
+		return review.User{}, review.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *UserRepo) ListByIDs(ctx context.Context, ids []string) ([]review.User, error) {
+	defer r.st.lock(ctx)()
+
+	var users []review.User
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		if u, ok := r.st.users[id]; ok {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+func (r *UserRepo) ListActiveByTeam(ctx context.Context, teamName string) ([]review.User, error) {
+	defer r.st.lock(ctx)()
+
+	return r.st.usersByTeam(teamName, true), nil
+}
+
+func (r *UserRepo) ListByTeam(ctx context.Context, teamName string) ([]review.User, error) {
+	defer r.st.lock(ctx)()
+
+	return r.st.usersByTeam(teamName, false), nil
+}
+
+func (s *Store) usersByTeam(teamName string, activeOnly bool) []review.User {
+	var users []review.User
+	for _, u := range s.users {
+		if u.Team != teamName || (activeOnly && !u.IsActive) {
+			continue
+		}
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// teamExists сообщает, можно ли сохранить пользователя с такой командой;
+// пустое имя означает пользователя без команды.
+func (s *Store) teamExists(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, ok := s.teams[name]
+	return ok
+}
+
+func (r *UserRepo) SetActive(ctx context.Context, userIDs []string, active bool) error {
+	defer r.st.lock(ctx)()
+
+	for _, id := range userIDs {
+		if u, ok := r.st.users[id]; ok {
+			u.IsActive = active
+			put(r.st, r.st.users, id, u)
+		}
+	}
+	return nil
+}
+
+func (r *UserRepo) AddAbsence(ctx context.Context, a review.Absence) (review.Absence, error) {
+	defer r.st.lock(ctx)()
+
+	if _, ok := r.st.users[a.UserID]; !ok {
+		return review.Absence{}, review.ErrNotFound
+	}
+	r.st.nextAbsenceID++
+	a.ID = r.st.nextAbsenceID
+	r.st.absences = append(r.st.absences, a)
+	return a, nil
+}
+
+func (r *UserRepo) ListTeamAbsences(ctx context.Context, teamName string, from time.Time) ([]review.Absence, error) {
+	defer r.st.lock(ctx)()
+
+	var res []review.Absence
+	for _, a := range r.st.absences {
+		if r.st.users[a.UserID].Team != teamName || !a.EndsAt.After(from) {
+			continue
+		}
+		res = append(res, a)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].StartsAt.Equal(res[j].StartsAt) {
+			return res[i].StartsAt.Before(res[j].StartsAt)
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (r *UserRepo) SetTags(ctx context.Context, userID string, tags []string) error {
+	defer r.st.lock(ctx)()
+
+	u, ok := r.st.users[userID]
+	if !ok {
+		return review.ErrNotFound
+	}
+	u.Tags = nil
+	if len(tags) > 0 {
+		u.Tags = append([]string(nil), tags...)
+		sort.Strings(u.Tags)
+	}
+	put(r.st, r.st.users, userID, u)
+	return nil
+}
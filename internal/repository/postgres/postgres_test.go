@@ -0,0 +1,82 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zapevnik/pr-review-service/internal/repository/postgres"
+	"github.com/zapevnik/pr-review-service/internal/repository/repotest"
+)
+
+// dsnEnv задаёт PostgreSQL для контрактных тестов. Каждый тест получает
+// собственную схему, которая удаляется после завершения.
+const dsnEnv = "PR_REVIEW_TEST_POSTGRES_DSN"
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, factory(t))
+}
+
+func BenchmarkDeactivateTeamUsers(b *testing.B) {
+	repotest.BenchmarkDeactivateTeamUsers(b, factory(b))
+}
+
+// factory подключается к PostgreSQL из dsnEnv или пропускает тест.
+func factory(tb testing.TB) repotest.Factory {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		// В CI PostgreSQL поднимается всегда, и пропуск означал бы, что
+		// контракт незаметно перестал проверяться.
+		if os.Getenv("CI") != "" {
+			tb.Fatalf("%s is not set in CI", dsnEnv)
+		}
+		tb.Skipf("%s is not set", dsnEnv)
+	}
+	logg := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		tb.Fatalf("open postgres: %v", err)
+	}
+	tb.Cleanup(func() { _ = admin.Close() })
+
+	return func(t testing.TB) repotest.Repos {
+		ctx := context.Background()
+		schema := fmt.Sprintf("repotest_%d", time.Now().UnixNano())
+
+		if _, err := admin.ExecContext(ctx, `CREATE SCHEMA `+schema); err != nil {
+			t.Fatalf("create schema: %v", err)
+		}
+		t.Cleanup(func() { _, _ = admin.ExecContext(ctx, `DROP SCHEMA `+schema+` CASCADE`) })
+
+		db, err := postgres.New(logg, ctx, postgres.Config{
+			DSN:           withSearchPath(dsn, schema),
+			MigrationsDir: "../../../migrations",
+		})
+		if err != nil {
+			t.Fatalf("open postgres: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+
+		return repotest.Repos{
+			PRs:   postgres.NewPRRepo(db, logg),
+			Users: postgres.NewUserRepo(db, logg),
+			Teams: postgres.NewTeamRepo(db, logg),
+			Tx:    postgres.NewTxManager(db, logg),
+		}
+	}
+}
+
+func withSearchPath(dsn, schema string) string {
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "search_path=" + schema
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"log/slog"
 
+	"github.com/lib/pq"
 	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/repository/sqltx"
 )
 
 type TeamRepo struct {
@@ -21,7 +23,7 @@ func (r *TeamRepo) Create(ctx context.Context, team review.Team) (review.Team, e
 	r.log.Info("creating team", "team_name", team.Name)
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx,
+	err := sqltx.Conn(ctx, r.db).QueryRowContext(ctx,
 		`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, team.Name).Scan(&exists)
 	if err != nil {
 		r.log.Error("failed to check team existence", "error", err, "team_name", team.Name)
@@ -32,7 +34,10 @@ func (r *TeamRepo) Create(ctx context.Context, team review.Team) (review.Team, e
 		return review.Team{}, review.ErrTeamExists
 	}
 
-	_, err = r.db.ExecContext(ctx, `INSERT INTO teams (team_name) VALUES ($1)`, team.Name)
+	_, err = sqltx.Conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO teams (team_name, min_reviewers, max_reviewers, max_open_reviews) VALUES ($1, $2, $3, NULLIF($4, 0))`,
+		team.Name, team.MinReviewers, team.MaxReviewers, team.MaxOpenReviews,
+	)
 	if err != nil {
 		r.log.Error("failed to insert team", "error", err, "team_name", team.Name)
 		return review.Team{}, err
@@ -46,7 +51,9 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (review.Team, err
 	r.log.Info("fetching team by name", "team_name", name)
 
 	var t review.Team
-	err := r.db.QueryRowContext(ctx, `SELECT team_name FROM teams WHERE team_name=$1`, name).Scan(&t.Name)
+	err := sqltx.Conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT team_name, min_reviewers, max_reviewers, COALESCE(max_open_reviews, 0) FROM teams WHERE team_name=$1`, name,
+	).Scan(&t.Name, &t.MinReviewers, &t.MaxReviewers, &t.MaxOpenReviews)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			r.log.Warn("team not found", "team_name", name)
@@ -58,3 +65,290 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (review.Team, err
 
 	return t, nil
 }
+
+func (r *TeamRepo) Update(ctx context.Context, team review.Team) (review.Team, error) {
+	r.log.Info("updating team", "team_name", team.Name)
+
+	res, err := sqltx.Conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE teams SET min_reviewers=$1, max_reviewers=$2, max_open_reviews=NULLIF($3, 0) WHERE team_name=$4`,
+		team.MinReviewers, team.MaxReviewers, team.MaxOpenReviews, team.Name,
+	)
+	if err != nil {
+		r.log.Error("failed to update team", "error", err, "team_name", team.Name)
+		return review.Team{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		r.log.Warn("team not found for update", "team_name", team.Name)
+		return review.Team{}, review.ErrNotFound
+	}
+
+	return r.GetByName(ctx, team.Name)
+}
+
+func (r *TeamRepo) Rename(ctx context.Context, oldName, newName string) (review.Team, error) {
+	r.log.Info("renaming team", "team_name", oldName, "new_name", newName)
+
+	// users.team_name обновляется каскадом по внешнему ключу.
+	res, err := sqltx.Conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE teams SET team_name=$1 WHERE team_name=$2`, newName, oldName,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			r.log.Warn("team already exists", "team_name", newName)
+			return review.Team{}, review.ErrTeamExists
+		}
+		r.log.Error("failed to rename team", "error", err, "team_name", oldName)
+		return review.Team{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		r.log.Warn("team not found for rename", "team_name", oldName)
+		return review.Team{}, review.ErrNotFound
+	}
+
+	r.log.Info("team renamed successfully", "team_name", oldName, "new_name", newName)
+	return r.GetByName(ctx, newName)
+}
+
+func (r *TeamRepo) Delete(ctx context.Context, name string) error {
+	r.log.Info("deleting team", "team_name", name)
+
+	res, err := sqltx.Conn(ctx, r.db).ExecContext(ctx, `DELETE FROM teams WHERE team_name=$1`, name)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+			r.log.Warn("team still has members", "team_name", name)
+			return review.ErrTeamNotEmpty
+		}
+		r.log.Error("failed to delete team", "error", err, "team_name", name)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		r.log.Warn("team not found for delete", "team_name", name)
+		return review.ErrNotFound
+	}
+
+	r.log.Info("team deleted successfully", "team_name", name)
+	return nil
+}
+
+func (r *TeamRepo) SetFallbacks(ctx context.Context, teamName string, fallbacks []string) error {
+	r.log.Info("setting fallback teams", "team_name", teamName, "fallbacks", fallbacks)
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, teamName).Scan(&exists)
+	if err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to check team existence", "error", err, "team_name", teamName)
+		return err
+	}
+	if !exists {
+		_ = tx.Rollback()
+		r.log.Warn("team not found for fallbacks", "team_name", teamName)
+		return review.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_fallbacks WHERE team_name=$1`, teamName); err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to delete fallback teams", "error", err, "team_name", teamName)
+		return err
+	}
+
+	for i, fb := range fallbacks {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO team_fallbacks (team_name, fallback_team, position) VALUES ($1, $2, $3)`,
+			teamName, fb, i,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+				r.log.Warn("fallback team not found", "team_name", teamName, "fallback", fb)
+				return review.ErrNotFound
+			}
+			r.log.Error("failed to insert fallback team", "error", err, "team_name", teamName, "fallback", fb)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "team_name", teamName)
+		return err
+	}
+	return nil
+}
+
+func (r *TeamRepo) ListFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	r.log.Info("listing fallback teams", "team_name", teamName)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT fallback_team FROM team_fallbacks WHERE team_name=$1 ORDER BY position`, teamName,
+	)
+	if err != nil {
+		r.log.Error("failed to query fallback teams", "error", err, "team_name", teamName)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fallbacks []string
+	for rows.Next() {
+		var fb string
+		if err := rows.Scan(&fb); err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, fb)
+	}
+	return fallbacks, rows.Err()
+}
+
+func (r *TeamRepo) SetRoleQuotas(ctx context.Context, teamName string, quotas []review.RoleQuota) error {
+	r.log.Info("setting role quotas", "team_name", teamName, "quotas", quotas)
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, teamName).Scan(&exists)
+	if err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to check team existence", "error", err, "team_name", teamName)
+		return err
+	}
+	if !exists {
+		_ = tx.Rollback()
+		r.log.Warn("team not found for role quotas", "team_name", teamName)
+		return review.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_role_quotas WHERE team_name=$1`, teamName); err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to delete role quotas", "error", err, "team_name", teamName)
+		return err
+	}
+
+	for i, q := range quotas {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO team_role_quotas (team_name, seniority, reviewers_count, position) VALUES ($1, $2, $3, $4)`,
+			teamName, q.Seniority, q.Count, i,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			r.log.Error("failed to insert role quota", "error", err, "team_name", teamName, "seniority", q.Seniority)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "team_name", teamName)
+		return err
+	}
+	return nil
+}
+
+func (r *TeamRepo) ListRoleQuotas(ctx context.Context, teamName string) ([]review.RoleQuota, error) {
+	r.log.Info("listing role quotas", "team_name", teamName)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT seniority, reviewers_count FROM team_role_quotas WHERE team_name=$1 ORDER BY position`, teamName,
+	)
+	if err != nil {
+		r.log.Error("failed to query role quotas", "error", err, "team_name", teamName)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotas []review.RoleQuota
+	for rows.Next() {
+		var q review.RoleQuota
+		if err := rows.Scan(&q.Seniority, &q.Count); err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, q)
+	}
+	return quotas, rows.Err()
+}
+
+func (r *TeamRepo) SetReviewerRules(ctx context.Context, teamName string, rules []review.ReviewerRule) error {
+	r.log.Info("setting reviewer rules", "team_name", teamName, "rules_count", len(rules))
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, teamName).Scan(&exists)
+	if err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to check team existence", "error", err, "team_name", teamName)
+		return err
+	}
+	if !exists {
+		_ = tx.Rollback()
+		r.log.Warn("team not found for reviewer rules", "team_name", teamName)
+		return review.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reviewer_rules WHERE team_name=$1`, teamName); err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to delete reviewer rules", "error", err, "team_name", teamName)
+		return err
+	}
+
+	for i, rule := range rules {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO reviewer_rules (team_name, author_id, reviewer_id, rule_type, position)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			teamName, rule.AuthorID, rule.ReviewerID, rule.Type, i,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23503" {
+				r.log.Warn("user not found for reviewer rule", "team_name", teamName,
+					"author_id", rule.AuthorID, "reviewer_id", rule.ReviewerID)
+				return review.ErrNotFound
+			}
+			r.log.Error("failed to insert reviewer rule", "error", err, "team_name", teamName)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "team_name", teamName)
+		return err
+	}
+	return nil
+}
+
+func (r *TeamRepo) ListReviewerRules(ctx context.Context, teamName string) ([]review.ReviewerRule, error) {
+	r.log.Info("listing reviewer rules", "team_name", teamName)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT rule_type, author_id, reviewer_id FROM reviewer_rules WHERE team_name=$1 ORDER BY position`, teamName,
+	)
+	if err != nil {
+		r.log.Error("failed to query reviewer rules", "error", err, "team_name", teamName)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []review.ReviewerRule
+	for rows.Next() {
+		var rule review.ReviewerRule
+		if err := rows.Scan(&rule.Type, &rule.AuthorID, &rule.ReviewerID); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
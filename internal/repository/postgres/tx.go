@@ -0,0 +1,12 @@
+package postgres
+
+import (
+	"log/slog"
+
+	"github.com/zapevnik/pr-review-service/internal/repository/sqltx"
+)
+
+// NewTxManager возвращает менеджер транзакций поверх соединения db.
+func NewTxManager(db *DB, l *slog.Logger) *sqltx.TxManager {
+	return sqltx.NewTxManager(db.sql, l)
+}
@@ -0,0 +1,78 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+)
+
+// BenchmarkDeactivateTeamUsers измеряет /team/deactivateUsers на хранилище:
+// пять участников команды из двадцати снимаются с 400 OPEN-PR разных авторов.
+// Каждая итерация получает свежее хранилище, подготовка в замер не входит.
+func BenchmarkDeactivateTeamUsers(b *testing.B, newRepos Factory) {
+	const (
+		members = 20
+		prs     = 400
+	)
+	logg := slog.New(slog.NewTextHandler(io.Discard, nil))
+	deactivated := []string{"u00", "u01", "u02", "u03", "u04"}
+
+	for range b.N {
+		b.StopTimer()
+		r := newRepos(b)
+		seedDeactivation(b, r, members, prs)
+		s := review.NewService(r.PRs, r.Users, r.Teams, r.Tx,
+			review.Selectors{Default: review.NewLeastLoadedSelector(rand.New(rand.NewSource(1)))},
+			review.MergePolicy{}, rand.New(rand.NewSource(1)), logg)
+		b.StartTimer()
+
+		report, err := s.DeactivateTeamUsers(context.Background(), "backend", deactivated)
+		if err != nil {
+			b.Fatalf("deactivate: %v", err)
+		}
+		if len(report.Reassigned) == 0 || len(report.Failed) != 0 {
+			b.Fatalf("reassigned %d, failed %d; want all reassigned", len(report.Reassigned), len(report.Failed))
+		}
+	}
+}
+
+// seedDeactivation создаёт команду backend и PR, у каждого из которых два
+// ревьювера; авторы и ревьюверы идут по кругу, поэтому часть PR попадает на
+// деактивируемых u00–u04.
+func seedDeactivation(tb testing.TB, r Repos, members, prs int) {
+	tb.Helper()
+	ctx := context.Background()
+
+	if _, err := r.Teams.Create(ctx, review.Team{Name: "backend", MinReviewers: 2, MaxReviewers: 2}); err != nil {
+		tb.Fatalf("create team: %v", err)
+	}
+	ids := make([]string, members)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("u%02d", i)
+		u := review.User{ID: ids[i], Name: "name-" + ids[i], Team: "backend", IsActive: true}
+		if _, err := r.Users.Create(ctx, u); err != nil {
+			tb.Fatalf("create user %q: %v", u.ID, err)
+		}
+	}
+	for i := range prs {
+		author := ids[i%members]
+		reviewers := []string{ids[(i+1)%members], ids[(i+2)%members]}
+		_, err := r.PRs.Create(ctx, review.PullRequest{
+			ID:          fmt.Sprintf("pr%03d", i),
+			Title:       "title",
+			AuthorID:    author,
+			Status:      review.StatusOpen,
+			CreatedAt:   baseTime.Add(time.Duration(i) * time.Second),
+			ReviewerIDs: reviewers,
+		})
+		if err != nil {
+			tb.Fatalf("create pr %d: %v", i, err)
+		}
+	}
+}
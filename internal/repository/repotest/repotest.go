@@ -0,0 +1,1366 @@
+// Package repotest содержит контрактные тесты репозиториев review.
+// Любая реализация хранилища прогоняет Run против себя, чтобы сервис
+// вёл себя одинаково независимо от выбранного backend'а.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+)
+
+// Repos — репозитории одного хранилища, на которых проверяется контракт.
+type Repos struct {
+	PRs   review.PRRepository
+	Users review.UserRepository
+	Teams review.TeamRepository
+	Tx    review.TxManager
+}
+
+// Factory создаёт пустое хранилище для одного теста или итерации бенчмарка.
+type Factory func(tb testing.TB) Repos
+
+// Run прогоняет весь контракт; каждый подтест получает свежее хранилище.
+func Run(t *testing.T, newRepos Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r Repos)
+	}{
+		{"Team/CreateAndGet", testTeamCreateAndGet},
+		{"Team/CreateDuplicate", testTeamCreateDuplicate},
+		{"Team/GetMissing", testTeamGetMissing},
+		{"Team/Update", testTeamUpdate},
+		{"Team/Rename", testTeamRename},
+		{"Team/Delete", testTeamDelete},
+		{"Team/Fallbacks", testTeamFallbacks},
+		{"Team/RoleQuotas", testTeamRoleQuotas},
+		{"Team/ReviewerRules", testTeamReviewerRules},
+
+		{"User/CreateAndGet", testUserCreateAndGet},
+		{"User/CreateUnknownTeam", testUserCreateUnknownTeam},
+		{"User/GetMissing", testUserGetMissing},
+		{"User/Update", testUserUpdate},
+		{"User/UpdateMissing", testUserUpdateMissing},
+		{"User/ListByTeam", testUserListByTeam},
+		{"User/ListByIDs", testUserListByIDs},
+		{"User/Teamless", testUserTeamless},
+		{"User/SetActive", testUserSetActive},
+		{"User/Absences", testUserAbsences},
+		{"User/AbsenceUnknownUser", testUserAbsenceUnknownUser},
+		{"User/Tags", testUserTags},
+
+		{"PR/CreateAndGet", testPRCreateAndGet},
+		{"PR/CreateDuplicate", testPRCreateDuplicate},
+		{"PR/CreateUnknownAuthor", testPRCreateUnknownAuthor},
+		{"PR/GetMissing", testPRGetMissing},
+		{"PR/Update", testPRUpdate},
+		{"PR/UpdateClosedFrom", testPRUpdateClosedFrom},
+		{"PR/UpdateStaleVersion", testPRUpdateStaleVersion},
+		{"PR/UpdateMissing", testPRUpdateMissing},
+		{"PR/AddReview", testPRAddReview},
+		{"PR/AddReviewMissingPR", testPRAddReviewMissingPR},
+		{"PR/AddReviewConflict", testPRAddReviewConflict},
+		{"PR/Events", testPREvents},
+		{"PR/ListAssignedTo", testPRListAssignedTo},
+		{"PR/ListReviewerStats", testPRListReviewerStats},
+		{"PR/ListReviewerStatsCapacity", testPRListReviewerStatsCapacity},
+		{"PR/ListReviewerStatsLastAssigned", testPRListReviewerStatsLastAssigned},
+		{"PR/ListOpenByReviewers", testPRListOpenByReviewers},
+		{"PR/ReplaceReviewers", testPRReplaceReviewers},
+		{"PR/ReplaceReviewersConflict", testPRReplaceReviewersConflict},
+		{"PR/ReviewerTeams", testPRReviewerTeams},
+		{"PR/RepositoryAndFiles", testPRRepositoryAndFiles},
+		{"PR/OwnershipRules", testPROwnershipRules},
+
+		{"Tx/Commit", testTxCommit},
+		{"Tx/Rollback", testTxRollback},
+		{"Tx/Nested", testTxNested},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newRepos(t))
+		})
+	}
+}
+
+// baseTime округлён до микросекунд — это точность timestamp в PostgreSQL.
+var baseTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func seedTeam(t *testing.T, r Repos, name string, users ...review.User) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := r.Teams.Create(ctx, review.Team{
+		Name:         name,
+		MinReviewers: review.DefaultMinReviewers,
+		MaxReviewers: review.DefaultMaxReviewers,
+	}); err != nil {
+		t.Fatalf("create team %q: %v", name, err)
+	}
+	for _, u := range users {
+		u.Team = name
+		if _, err := r.Users.Create(ctx, u); err != nil {
+			t.Fatalf("create user %q: %v", u.ID, err)
+		}
+	}
+}
+
+func user(id string, active bool) review.User {
+	return review.User{ID: id, Name: "name-" + id, IsActive: active}
+}
+
+func seedPR(t *testing.T, r Repos, id, author string, status review.PRStatus, createdAt time.Time, reviewers ...string) review.PullRequest {
+	t.Helper()
+
+	pr, err := r.PRs.Create(context.Background(), review.PullRequest{
+		ID:          id,
+		Title:       "title-" + id,
+		AuthorID:    author,
+		Status:      status,
+		CreatedAt:   createdAt,
+		ReviewerIDs: reviewers,
+	})
+	if err != nil {
+		t.Fatalf("create pr %q: %v", id, err)
+	}
+	return pr
+}
+
+func wantErr(t *testing.T, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Fatalf("error = %v, want %v", got, want)
+	}
+}
+
+func noErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func sortedIDs(ids []string) []string {
+	out := append([]string(nil), ids...)
+	sort.Strings(out)
+	return out
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testTeamCreateAndGet(t *testing.T, r Repos) {
+	ctx := context.Background()
+	team := review.Team{Name: "backend", MinReviewers: 0, MaxReviewers: 3, MaxOpenReviews: 5}
+
+	created, err := r.Teams.Create(ctx, team)
+	noErr(t, err)
+	if created != team {
+		t.Fatalf("Create = %+v, want %+v", created, team)
+	}
+
+	got, err := r.Teams.GetByName(ctx, "backend")
+	noErr(t, err)
+	if got != team {
+		t.Fatalf("GetByName = %+v, want %+v", got, team)
+	}
+}
+
+func testTeamUpdate(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend")
+	ctx := context.Background()
+
+	want := review.Team{Name: "backend", MinReviewers: 2, MaxReviewers: 4, MaxOpenReviews: 3}
+	got, err := r.Teams.Update(ctx, want)
+	noErr(t, err)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Update = %+v, want %+v", got, want)
+	}
+
+	want.MaxOpenReviews = 0
+	got, err = r.Teams.Update(ctx, want)
+	noErr(t, err)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Update without limit = %+v, want %+v", got, want)
+	}
+
+	_, err = r.Teams.Update(ctx, review.Team{Name: "nope", MinReviewers: 1, MaxReviewers: 2})
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testTeamCreateDuplicate(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend")
+
+	_, err := r.Teams.Create(context.Background(), review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 2})
+	wantErr(t, err, review.ErrTeamExists)
+}
+
+func testTeamGetMissing(t *testing.T, r Repos) {
+	_, err := r.Teams.GetByName(context.Background(), "nope")
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testTeamRename(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true), user("u2", false))
+	seedTeam(t, r, "frontend")
+	ctx := context.Background()
+
+	renamed, err := r.Teams.Rename(ctx, "backend", "platform")
+	noErr(t, err)
+	if renamed.Name != "platform" || renamed.MaxReviewers != review.DefaultMaxReviewers {
+		t.Fatalf("Rename = %+v", renamed)
+	}
+
+	_, err = r.Teams.GetByName(ctx, "backend")
+	wantErr(t, err, review.ErrNotFound)
+
+	members, err := r.Users.ListByTeam(ctx, "platform")
+	noErr(t, err)
+	if ids := userIDs(members); !equalIDs(ids, []string{"u1", "u2"}) {
+		t.Fatalf("members after rename = %v, want [u1 u2]", ids)
+	}
+	u, err := r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+	if u.Team != "platform" {
+		t.Fatalf("user team after rename = %q, want platform", u.Team)
+	}
+
+	_, err = r.Teams.Rename(ctx, "platform", "frontend")
+	wantErr(t, err, review.ErrTeamExists)
+	_, err = r.Teams.Rename(ctx, "nope", "other")
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testTeamDelete(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true))
+	seedTeam(t, r, "empty")
+	ctx := context.Background()
+
+	noErr(t, r.Teams.Delete(ctx, "empty"))
+	_, err := r.Teams.GetByName(ctx, "empty")
+	wantErr(t, err, review.ErrNotFound)
+
+	wantErr(t, r.Teams.Delete(ctx, "backend"), review.ErrTeamNotEmpty)
+	wantErr(t, r.Teams.Delete(ctx, "nope"), review.ErrNotFound)
+
+	_, err = r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+}
+
+func testTeamFallbacks(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend")
+	seedTeam(t, r, "platform")
+	seedTeam(t, r, "infra")
+	ctx := context.Background()
+
+	fallbacks, err := r.Teams.ListFallbacks(ctx, "backend")
+	noErr(t, err)
+	if len(fallbacks) != 0 {
+		t.Fatalf("initial fallbacks = %v, want none", fallbacks)
+	}
+
+	noErr(t, r.Teams.SetFallbacks(ctx, "backend", []string{"platform", "infra"}))
+	noErr(t, r.Teams.SetFallbacks(ctx, "backend", []string{"infra", "platform"}))
+	fallbacks, err = r.Teams.ListFallbacks(ctx, "backend")
+	noErr(t, err)
+	if !equalIDs(fallbacks, []string{"infra", "platform"}) {
+		t.Fatalf("fallbacks = %v, want [infra platform] in order", fallbacks)
+	}
+
+	wantErr(t, r.Teams.SetFallbacks(ctx, "backend", []string{"nope"}), review.ErrNotFound)
+	wantErr(t, r.Teams.SetFallbacks(ctx, "nope", nil), review.ErrNotFound)
+	fallbacks, err = r.Teams.ListFallbacks(ctx, "backend")
+	noErr(t, err)
+	if !equalIDs(fallbacks, []string{"infra", "platform"}) {
+		t.Fatalf("fallbacks after failed set = %v, want unchanged", fallbacks)
+	}
+
+	_, err = r.Teams.Rename(ctx, "infra", "sre")
+	noErr(t, err)
+	noErr(t, r.Teams.Delete(ctx, "platform"))
+	fallbacks, err = r.Teams.ListFallbacks(ctx, "backend")
+	noErr(t, err)
+	if !equalIDs(fallbacks, []string{"sre"}) {
+		t.Fatalf("fallbacks after rename and delete = %v, want [sre]", fallbacks)
+	}
+
+	noErr(t, r.Teams.SetFallbacks(ctx, "backend", nil))
+	fallbacks, err = r.Teams.ListFallbacks(ctx, "backend")
+	noErr(t, err)
+	if len(fallbacks) != 0 {
+		t.Fatalf("fallbacks after reset = %v, want none", fallbacks)
+	}
+}
+
+func testTeamRoleQuotas(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend")
+	ctx := context.Background()
+
+	quotas, err := r.Teams.ListRoleQuotas(ctx, "backend")
+	noErr(t, err)
+	if len(quotas) != 0 {
+		t.Fatalf("initial quotas = %v, want none", quotas)
+	}
+
+	want := []review.RoleQuota{
+		{Seniority: review.SenioritySenior, Count: 1},
+		{Seniority: review.SeniorityJunior, Count: 1},
+	}
+	noErr(t, r.Teams.SetRoleQuotas(ctx, "backend", []review.RoleQuota{{Seniority: review.SeniorityMiddle, Count: 2}}))
+	noErr(t, r.Teams.SetRoleQuotas(ctx, "backend", want))
+	quotas, err = r.Teams.ListRoleQuotas(ctx, "backend")
+	noErr(t, err)
+	if !reflect.DeepEqual(quotas, want) {
+		t.Fatalf("quotas = %v, want %v in order", quotas, want)
+	}
+
+	wantErr(t, r.Teams.SetRoleQuotas(ctx, "nope", want), review.ErrNotFound)
+
+	_, err = r.Teams.Rename(ctx, "backend", "core")
+	noErr(t, err)
+	quotas, err = r.Teams.ListRoleQuotas(ctx, "core")
+	noErr(t, err)
+	if !reflect.DeepEqual(quotas, want) {
+		t.Fatalf("quotas after rename = %v, want %v", quotas, want)
+	}
+
+	noErr(t, r.Teams.SetRoleQuotas(ctx, "core", nil))
+	quotas, err = r.Teams.ListRoleQuotas(ctx, "core")
+	noErr(t, err)
+	if len(quotas) != 0 {
+		t.Fatalf("quotas after reset = %v, want none", quotas)
+	}
+}
+
+func testTeamReviewerRules(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("b", true), user("c", true))
+	ctx := context.Background()
+
+	rules, err := r.Teams.ListReviewerRules(ctx, "backend")
+	noErr(t, err)
+	if len(rules) != 0 {
+		t.Fatalf("initial rules = %v, want none", rules)
+	}
+
+	want := []review.ReviewerRule{
+		{Type: review.RuleExclude, AuthorID: "a", ReviewerID: "b"},
+		{Type: review.RulePrefer, AuthorID: "a", ReviewerID: "c"},
+		{Type: review.RuleExclude, AuthorID: "c", ReviewerID: "a"},
+	}
+	noErr(t, r.Teams.SetReviewerRules(ctx, "backend", want[:1]))
+	noErr(t, r.Teams.SetReviewerRules(ctx, "backend", want))
+	rules, err = r.Teams.ListReviewerRules(ctx, "backend")
+	noErr(t, err)
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("rules = %v, want %v in order", rules, want)
+	}
+
+	wantErr(t, r.Teams.SetReviewerRules(ctx, "nope", want), review.ErrNotFound)
+	wantErr(t, r.Teams.SetReviewerRules(ctx, "backend", []review.ReviewerRule{
+		{Type: review.RuleExclude, AuthorID: "a", ReviewerID: "ghost"},
+	}), review.ErrNotFound)
+	rules, err = r.Teams.ListReviewerRules(ctx, "backend")
+	noErr(t, err)
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("rules after failed set = %v, want unchanged", rules)
+	}
+
+	_, err = r.Teams.Rename(ctx, "backend", "core")
+	noErr(t, err)
+	rules, err = r.Teams.ListReviewerRules(ctx, "core")
+	noErr(t, err)
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("rules after rename = %v, want %v", rules, want)
+	}
+
+	noErr(t, r.Teams.SetReviewerRules(ctx, "core", nil))
+	rules, err = r.Teams.ListReviewerRules(ctx, "core")
+	noErr(t, err)
+	if len(rules) != 0 {
+		t.Fatalf("rules after reset = %v, want none", rules)
+	}
+}
+
+func testUserCreateAndGet(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend")
+	ctx := context.Background()
+
+	u := review.User{ID: "u1", Team: "backend", Name: "Alice", IsActive: true, Seniority: review.SeniorityJunior}
+	created, err := r.Users.Create(ctx, u)
+	noErr(t, err)
+	if !reflect.DeepEqual(created, u) {
+		t.Fatalf("Create = %+v, want %+v", created, u)
+	}
+
+	got, err := r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+	if !reflect.DeepEqual(got, u) {
+		t.Fatalf("GetByID = %+v, want %+v", got, u)
+	}
+}
+
+func testUserCreateUnknownTeam(t *testing.T, r Repos) {
+	_, err := r.Users.Create(context.Background(), review.User{ID: "u1", Team: "nope", Name: "Alice", IsActive: true})
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testUserGetMissing(t *testing.T, r Repos) {
+	_, err := r.Users.GetByID(context.Background(), "nope")
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testUserUpdate(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true))
+	seedTeam(t, r, "frontend")
+	ctx := context.Background()
+
+	want := review.User{ID: "u1", Team: "frontend", Name: "Renamed", IsActive: false, MaxOpenReviews: 4, Seniority: review.SenioritySenior}
+	got, err := r.Users.Update(ctx, want)
+	noErr(t, err)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Update = %+v, want %+v", got, want)
+	}
+
+	got, err = r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetByID after update = %+v, want %+v", got, want)
+	}
+
+	_, err = r.Users.Update(ctx, review.User{ID: "u1", Team: "nope", Name: "Renamed"})
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testUserUpdateMissing(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend")
+
+	_, err := r.Users.Update(context.Background(), review.User{ID: "nope", Team: "backend", Name: "X", IsActive: true})
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testUserListByTeam(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u3", true), user("u1", false), user("u2", true))
+	seedTeam(t, r, "frontend", user("f1", true))
+	ctx := context.Background()
+
+	all, err := r.Users.ListByTeam(ctx, "backend")
+	noErr(t, err)
+	if ids := userIDs(all); !equalIDs(ids, []string{"u1", "u2", "u3"}) {
+		t.Fatalf("ListByTeam = %v, want [u1 u2 u3]", ids)
+	}
+
+	active, err := r.Users.ListActiveByTeam(ctx, "backend")
+	noErr(t, err)
+	if ids := userIDs(active); !equalIDs(ids, []string{"u2", "u3"}) {
+		t.Fatalf("ListActiveByTeam = %v, want [u2 u3]", ids)
+	}
+
+	empty, err := r.Users.ListByTeam(ctx, "nope")
+	noErr(t, err)
+	if len(empty) != 0 {
+		t.Fatalf("ListByTeam for unknown team = %v, want empty", empty)
+	}
+}
+
+func testUserListByIDs(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u2", true), user("u1", false))
+	seedTeam(t, r, "frontend", user("f1", true))
+	ctx := context.Background()
+	noErr(t, r.Users.SetTags(ctx, "f1", []string{"go"}))
+
+	users, err := r.Users.ListByIDs(ctx, []string{"f1", "u1", "nope", "u2", "f1"})
+	noErr(t, err)
+	if ids := userIDs(users); !equalIDs(ids, []string{"f1", "u1", "u2"}) {
+		t.Fatalf("ListByIDs = %v, want [f1 u1 u2]", ids)
+	}
+	if f1 := users[0]; f1.Team != "frontend" || !f1.IsActive || len(f1.Tags) != 1 || f1.Tags[0] != "go" {
+		t.Fatalf("f1 = %+v, want active frontend user with tag go", f1)
+	}
+	if users[1].Team != "backend" || users[1].IsActive {
+		t.Fatalf("u1 = %+v, want inactive backend user", users[1])
+	}
+
+	empty, err := r.Users.ListByIDs(ctx, nil)
+	noErr(t, err)
+	if len(empty) != 0 {
+		t.Fatalf("ListByIDs(nil) = %v, want empty", empty)
+	}
+}
+
+func testUserTeamless(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+	ctx := context.Background()
+
+	_, err := r.Users.Update(ctx, review.User{ID: "u1", Team: "", Name: "name-u1", IsActive: true})
+	noErr(t, err)
+	_, err = r.Users.Create(ctx, review.User{ID: "solo", Team: "", Name: "Solo", IsActive: true})
+	noErr(t, err)
+
+	u, err := r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+	if u.Team != "" {
+		t.Fatalf("teamless user team = %q, want empty", u.Team)
+	}
+
+	members, err := r.Users.ListByTeam(ctx, "backend")
+	noErr(t, err)
+	if ids := userIDs(members); !equalIDs(ids, []string{"u2"}) {
+		t.Fatalf("members = %v, want [u2]", ids)
+	}
+	stats, err := r.PRs.ListReviewerStats(ctx, "backend")
+	noErr(t, err)
+	if len(stats) != 1 || stats[0].UserID != "u2" {
+		t.Fatalf("stats = %+v, want only u2", stats)
+	}
+}
+
+func testUserSetActive(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true), user("u2", true), user("u3", true))
+	ctx := context.Background()
+
+	noErr(t, r.Users.SetActive(ctx, []string{"u1", "u3", "nope"}, false))
+
+	active, err := r.Users.ListActiveByTeam(ctx, "backend")
+	noErr(t, err)
+	if ids := userIDs(active); !equalIDs(ids, []string{"u2"}) {
+		t.Fatalf("active after SetActive = %v, want [u2]", ids)
+	}
+
+	noErr(t, r.Users.SetActive(ctx, []string{"u1"}, true))
+	u, err := r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+	if !u.IsActive {
+		t.Fatalf("u1 not reactivated: %+v", u)
+	}
+}
+
+func testUserAbsences(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+	seedTeam(t, r, "frontend", user("f1", true))
+	ctx := context.Background()
+
+	// Дробные секунды разной длины проверяют, что сравнение времени в
+	// хранилище не зависит от формата записи.
+	add := func(userID string, start, end time.Duration, reason string) review.Absence {
+		t.Helper()
+		a, err := r.Users.AddAbsence(ctx, review.Absence{
+			UserID:   userID,
+			StartsAt: baseTime.Add(start),
+			EndsAt:   baseTime.Add(end),
+			Reason:   reason,
+		})
+		noErr(t, err)
+		if a.ID == 0 {
+			t.Fatalf("AddAbsence returned zero ID")
+		}
+		return a
+	}
+	add("u1", -48*time.Hour, -24*time.Hour, "past")
+	current := add("u1", -time.Hour, 5*time.Millisecond, "vacation")
+	upcoming := add("u2", 24*time.Hour+500*time.Microsecond, 72*time.Hour, "conference")
+	add("f1", 0, time.Hour, "other team")
+
+	got, err := r.Users.ListTeamAbsences(ctx, "backend", baseTime)
+	noErr(t, err)
+	if len(got) != 2 || got[0].ID != current.ID || got[1].ID != upcoming.ID {
+		t.Fatalf("ListTeamAbsences = %+v, want [%d %d]", got, current.ID, upcoming.ID)
+	}
+	a := got[1]
+	if a.UserID != "u2" || a.Reason != "conference" ||
+		!a.StartsAt.Equal(upcoming.StartsAt) || !a.EndsAt.Equal(upcoming.EndsAt) {
+		t.Fatalf("absence = %+v, want %+v", a, upcoming)
+	}
+
+	got, err = r.Users.ListTeamAbsences(ctx, "backend", baseTime.Add(5*time.Millisecond))
+	noErr(t, err)
+	if len(got) != 1 || got[0].ID != upcoming.ID {
+		t.Fatalf("ListTeamAbsences after current ended = %+v, want [%d]", got, upcoming.ID)
+	}
+}
+
+func testUserAbsenceUnknownUser(t *testing.T, r Repos) {
+	_, err := r.Users.AddAbsence(context.Background(), review.Absence{
+		UserID:   "nope",
+		StartsAt: baseTime,
+		EndsAt:   baseTime.Add(time.Hour),
+	})
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testUserTags(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+	ctx := context.Background()
+
+	noErr(t, r.Users.SetTags(ctx, "u1", []string{"sql", "go"}))
+	got, err := r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+	if !equalIDs(got.Tags, []string{"go", "sql"}) {
+		t.Fatalf("GetByID tags = %v, want [go sql]", got.Tags)
+	}
+
+	got.Name = "Renamed"
+	got.Tags = nil
+	_, err = r.Users.Update(ctx, got)
+	noErr(t, err)
+
+	members, err := r.Users.ListByTeam(ctx, "backend")
+	noErr(t, err)
+	if len(members) != 2 || !equalIDs(members[0].Tags, []string{"go", "sql"}) || members[1].Tags != nil {
+		t.Fatalf("ListByTeam = %+v, want u1 with [go sql] and u2 without tags", members)
+	}
+
+	stats, err := r.PRs.ListReviewerStats(ctx, "backend")
+	noErr(t, err)
+	for _, st := range stats {
+		if st.UserID == "u1" && !equalIDs(st.Tags, []string{"go", "sql"}) {
+			t.Fatalf("ListReviewerStats tags for u1 = %v, want [go sql]", st.Tags)
+		}
+	}
+
+	noErr(t, r.Users.SetTags(ctx, "u1", nil))
+	got, err = r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+	if got.Tags != nil {
+		t.Fatalf("tags after reset = %v, want none", got.Tags)
+	}
+
+	wantErr(t, r.Users.SetTags(ctx, "nope", []string{"go"}), review.ErrNotFound)
+}
+
+func userIDs(users []review.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func testPRCreateAndGet(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true))
+	ctx := context.Background()
+
+	created := seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime, "r2", "r1")
+	if created.Version != 1 {
+		t.Fatalf("Create version = %d, want 1", created.Version)
+	}
+
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.ID != "pr1" || got.Title != "title-pr1" || got.AuthorID != "a" || got.Status != review.StatusOpen {
+		t.Fatalf("GetByID = %+v", got)
+	}
+	if !got.CreatedAt.Equal(baseTime) {
+		t.Fatalf("CreatedAt = %v, want %v", got.CreatedAt, baseTime)
+	}
+	if got.MergedAt != nil || got.ClosedAt != nil || got.MergeForced {
+		t.Fatalf("new PR has merge/close state: %+v", got)
+	}
+	if ids := sortedIDs(got.ReviewerIDs); !equalIDs(ids, []string{"r1", "r2"}) {
+		t.Fatalf("ReviewerIDs = %v, want [r1 r2]", ids)
+	}
+	if got.Version != 1 || len(got.Reviews) != 0 {
+		t.Fatalf("Version = %d, Reviews = %v; want 1 and none", got.Version, got.Reviews)
+	}
+}
+
+func testPRCreateDuplicate(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true))
+	seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime)
+
+	_, err := r.PRs.Create(context.Background(), review.PullRequest{
+		ID: "pr1", Title: "again", AuthorID: "a", Status: review.StatusOpen, CreatedAt: baseTime,
+	})
+	wantErr(t, err, review.ErrPRExists)
+}
+
+func testPRCreateUnknownAuthor(t *testing.T, r Repos) {
+	_, err := r.PRs.Create(context.Background(), review.PullRequest{
+		ID: "pr1", Title: "t", AuthorID: "nope", Status: review.StatusOpen, CreatedAt: baseTime,
+	})
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testPRGetMissing(t *testing.T, r Repos) {
+	_, err := r.PRs.GetByID(context.Background(), "nope")
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testPRUpdate(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true))
+	ctx := context.Background()
+	pr := seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime, "r1")
+
+	mergedAt := baseTime.Add(time.Hour)
+	pr.Title = "renamed"
+	pr.Status = review.StatusMerged
+	pr.MergedAt = &mergedAt
+	pr.MergeForced = true
+	pr.ReviewerIDs = []string{"r2"}
+
+	updated, err := r.PRs.Update(ctx, pr)
+	noErr(t, err)
+	if updated.Version != 2 {
+		t.Fatalf("Update version = %d, want 2", updated.Version)
+	}
+
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Title != "renamed" || got.Status != review.StatusMerged || !got.MergeForced {
+		t.Fatalf("GetByID after update = %+v", got)
+	}
+	if got.MergedAt == nil || !got.MergedAt.Equal(mergedAt) {
+		t.Fatalf("MergedAt = %v, want %v", got.MergedAt, mergedAt)
+	}
+	if !equalIDs(got.ReviewerIDs, []string{"r2"}) {
+		t.Fatalf("ReviewerIDs = %v, want [r2]", got.ReviewerIDs)
+	}
+	if !got.CreatedAt.Equal(baseTime) || got.AuthorID != "a" {
+		t.Fatalf("immutable fields changed: %+v", got)
+	}
+
+	got.ReviewerIDs = nil
+	got, err = r.PRs.Update(ctx, got)
+	noErr(t, err)
+	if len(got.ReviewerIDs) != 0 || got.Version != 3 {
+		t.Fatalf("after clearing reviewers: ReviewerIDs = %v, Version = %d", got.ReviewerIDs, got.Version)
+	}
+}
+
+func testPRUpdateClosedFrom(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true))
+	ctx := context.Background()
+	pr := seedPR(t, r, "pr1", "a", review.StatusDraft, baseTime)
+	if pr.ClosedFrom != "" {
+		t.Fatalf("new PR ClosedFrom = %q, want empty", pr.ClosedFrom)
+	}
+
+	closedAt := baseTime.Add(time.Hour)
+	pr.Status, pr.ClosedAt, pr.ClosedFrom = review.StatusClosed, &closedAt, review.StatusDraft
+	_, err := r.PRs.Update(ctx, pr)
+	noErr(t, err)
+
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Status != review.StatusClosed || got.ClosedFrom != review.StatusDraft {
+		t.Fatalf("closed PR = status %s closed_from %q, want CLOSED from DRAFT", got.Status, got.ClosedFrom)
+	}
+
+	got.Status, got.ClosedAt, got.ClosedFrom = review.StatusDraft, nil, ""
+	_, err = r.PRs.Update(ctx, got)
+	noErr(t, err)
+	got, err = r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Status != review.StatusDraft || got.ClosedFrom != "" || got.ClosedAt != nil {
+		t.Fatalf("reopened PR = %+v, want DRAFT without closed_from", got)
+	}
+}
+
+func testPRUpdateStaleVersion(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true))
+	ctx := context.Background()
+	pr := seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime)
+
+	first := pr
+	first.Title = "first"
+	_, err := r.PRs.Update(ctx, first)
+	noErr(t, err)
+
+	second := pr
+	second.Title = "second"
+	_, err = r.PRs.Update(ctx, second)
+	wantErr(t, err, review.ErrConflict)
+
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Title != "first" || got.Version != 2 {
+		t.Fatalf("stale update leaked: %+v", got)
+	}
+}
+
+func testPRUpdateMissing(t *testing.T, r Repos) {
+	_, err := r.PRs.Update(context.Background(), review.PullRequest{
+		ID: "nope", Title: "t", Status: review.StatusOpen, Version: 1,
+	})
+	wantErr(t, err, review.ErrNotFound)
+}
+
+func testPRAddReview(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true))
+	ctx := context.Background()
+	seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime, "r1", "r2")
+
+	first, err := r.PRs.AddReview(ctx, review.Review{
+		PRID: "pr1", ReviewerID: "r1", State: review.ReviewChangesRequested, Body: "fix it", SubmittedAt: baseTime.Add(time.Minute),
+	}, 1)
+	noErr(t, err)
+	second, err := r.PRs.AddReview(ctx, review.Review{
+		PRID: "pr1", ReviewerID: "r1", State: review.ReviewApproved, SubmittedAt: baseTime.Add(2 * time.Minute),
+	}, 2)
+	noErr(t, err)
+	if first.ID == 0 || second.ID <= first.ID {
+		t.Fatalf("review ids = %d, %d; want increasing non-zero", first.ID, second.ID)
+	}
+
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Version != 3 {
+		t.Fatalf("Version after two reviews = %d, want 3", got.Version)
+	}
+	if len(got.Reviews) != 2 {
+		t.Fatalf("Reviews = %+v, want 2", got.Reviews)
+	}
+	rv := got.Reviews[0]
+	if rv.ID != first.ID || rv.ReviewerID != "r1" || rv.State != review.ReviewChangesRequested ||
+		rv.Body != "fix it" || !rv.SubmittedAt.Equal(baseTime.Add(time.Minute)) {
+		t.Fatalf("first review = %+v", rv)
+	}
+	if got.Reviews[1].State != review.ReviewApproved {
+		t.Fatalf("second review = %+v", got.Reviews[1])
+	}
+}
+
+func testPRAddReviewMissingPR(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("r1", true))
+
+	_, err := r.PRs.AddReview(context.Background(), review.Review{
+		PRID: "nope", ReviewerID: "r1", State: review.ReviewApproved, SubmittedAt: baseTime,
+	}, 1)
+	wantErr(t, err, review.ErrNotFound)
+}
+
+// Ревью на устаревшей версии PR (например, PR успели смержить) не
+// сохраняется.
+func testPRAddReviewConflict(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true))
+	ctx := context.Background()
+	pr := seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime, "r1")
+
+	pr.Status = review.StatusMerged
+	_, err := r.PRs.Update(ctx, pr)
+	noErr(t, err)
+
+	_, err = r.PRs.AddReview(ctx, review.Review{
+		PRID: "pr1", ReviewerID: "r1", State: review.ReviewApproved, SubmittedAt: baseTime,
+	}, pr.Version, review.PREvent{PRID: "pr1", Type: review.EventReviewSubmitted, ActorID: "r1", CreatedAt: baseTime})
+	wantErr(t, err, review.ErrConflict)
+
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Version != pr.Version+1 || len(got.Reviews) != 0 {
+		t.Fatalf("PR after stale review = version %d reviews %+v, want version %d and no reviews", got.Version, got.Reviews, pr.Version+1)
+	}
+	events, err := r.PRs.ListEvents(ctx, "pr1")
+	noErr(t, err)
+	for _, ev := range events {
+		if ev.Type == review.EventReviewSubmitted {
+			t.Fatalf("stale review event stored: %+v", ev)
+		}
+	}
+}
+
+func testPREvents(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true))
+	ctx := context.Background()
+
+	pr, err := r.PRs.Create(ctx, review.PullRequest{
+		ID: "pr1", Title: "t", AuthorID: "a", Status: review.StatusOpen, CreatedAt: baseTime, ReviewerIDs: []string{"r1"},
+	},
+		review.PREvent{PRID: "pr1", Type: review.EventCreated, ActorID: "a", CreatedAt: baseTime},
+		review.PREvent{PRID: "pr1", Type: review.EventReviewerAssigned, NewReviewerID: "r1", Details: "random", CreatedAt: baseTime},
+	)
+	noErr(t, err)
+
+	pr.ReviewerIDs = []string{"r2"}
+	_, err = r.PRs.Update(ctx, pr, review.PREvent{
+		PRID: "pr1", Type: review.EventReviewerReassigned, ActorID: "a", OldReviewerID: "r1", NewReviewerID: "r2", CreatedAt: baseTime.Add(time.Minute),
+	})
+	noErr(t, err)
+
+	_, err = r.PRs.AddReview(ctx, review.Review{PRID: "pr1", ReviewerID: "r2", State: review.ReviewApproved, SubmittedAt: baseTime.Add(2 * time.Minute)}, 2,
+		review.PREvent{PRID: "pr1", Type: review.EventReviewSubmitted, ActorID: "r2", Details: "APPROVED", CreatedAt: baseTime.Add(2 * time.Minute)},
+	)
+	noErr(t, err)
+
+	events, err := r.PRs.ListEvents(ctx, "pr1")
+	noErr(t, err)
+
+	want := []review.EventType{review.EventCreated, review.EventReviewerAssigned, review.EventReviewerReassigned, review.EventReviewSubmitted}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want types %v", events, want)
+	}
+	for i, ev := range events {
+		if ev.Type != want[i] || ev.PRID != "pr1" || ev.ID == 0 {
+			t.Fatalf("event %d = %+v, want type %s", i, ev, want[i])
+		}
+		if i > 0 && ev.ID <= events[i-1].ID {
+			t.Fatalf("event ids not increasing: %d after %d", ev.ID, events[i-1].ID)
+		}
+	}
+	re := events[2]
+	if re.ActorID != "a" || re.OldReviewerID != "r1" || re.NewReviewerID != "r2" || !re.CreatedAt.Equal(baseTime.Add(time.Minute)) {
+		t.Fatalf("reassign event = %+v", re)
+	}
+	if events[0].OldReviewerID != "" || events[0].NewReviewerID != "" {
+		t.Fatalf("created event has reviewer ids: %+v", events[0])
+	}
+
+	none, err := r.PRs.ListEvents(ctx, "nope")
+	noErr(t, err)
+	if len(none) != 0 {
+		t.Fatalf("events for unknown PR = %+v, want empty", none)
+	}
+}
+
+func testPRListAssignedTo(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true))
+	ctx := context.Background()
+
+	seedPR(t, r, "pr-late", "a", review.StatusOpen, baseTime.Add(time.Hour), "r1")
+	seedPR(t, r, "pr-early", "a", review.StatusMerged, baseTime, "r1", "r2")
+	seedPR(t, r, "pr-other", "a", review.StatusOpen, baseTime, "r2")
+
+	prs, err := r.PRs.ListAssignedTo(ctx, "r1")
+	noErr(t, err)
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.ID)
+	}
+	if !equalIDs(ids, []string{"pr-early", "pr-late"}) {
+		t.Fatalf("ListAssignedTo = %v, want [pr-early pr-late]", ids)
+	}
+	if prs[0].Status != review.StatusMerged {
+		t.Fatalf("ListAssignedTo returned %+v, want full PR", prs[0])
+	}
+
+	none, err := r.PRs.ListAssignedTo(ctx, "a")
+	noErr(t, err)
+	if len(none) != 0 {
+		t.Fatalf("ListAssignedTo for author = %+v, want empty", none)
+	}
+}
+
+func testPRListReviewerStats(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend",
+		user("a", true), user("b", true), user("c", true), user("d", true), user("idle", false))
+	seedTeam(t, r, "frontend", user("f", true))
+	ctx := context.Background()
+
+	seedPR(t, r, "pr1", "f", review.StatusOpen, baseTime, "c", "b")
+	seedPR(t, r, "pr2", "f", review.StatusOpen, baseTime, "c", "idle")
+	seedPR(t, r, "pr3", "f", review.StatusMerged, baseTime, "a", "d")
+	seedPR(t, r, "pr4", "f", review.StatusClosed, baseTime, "a")
+
+	stats, err := r.PRs.ListReviewerStats(ctx, "backend")
+	noErr(t, err)
+
+	want := []review.ReviewerStats{
+		{UserID: "a", Username: "name-a", TeamName: "backend", AssignedOpenPRs: 0},
+		{UserID: "d", Username: "name-d", TeamName: "backend", AssignedOpenPRs: 0},
+		{UserID: "b", Username: "name-b", TeamName: "backend", AssignedOpenPRs: 1},
+		{UserID: "c", Username: "name-c", TeamName: "backend", AssignedOpenPRs: 2},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("ListReviewerStats = %+v, want %+v", stats, want)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(stats[i], want[i]) {
+			t.Fatalf("ListReviewerStats[%d] = %+v, want %+v", i, stats[i], want[i])
+		}
+	}
+}
+
+func testPRListReviewerStatsCapacity(t *testing.T, r Repos) {
+	ctx := context.Background()
+	_, err := r.Teams.Create(ctx, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 2, MaxOpenReviews: 3})
+	noErr(t, err)
+	for _, u := range []review.User{
+		{ID: "a", Team: "backend", Name: "name-a", IsActive: true},
+		{ID: "b", Team: "backend", Name: "name-b", IsActive: true, MaxOpenReviews: 1, Seniority: review.SenioritySenior},
+	} {
+		_, err := r.Users.Create(ctx, u)
+		noErr(t, err)
+	}
+
+	stats, err := r.PRs.ListReviewerStats(ctx, "backend")
+	noErr(t, err)
+	caps := map[string]int{}
+	levels := map[string]review.Seniority{}
+	for _, st := range stats {
+		caps[st.UserID] = st.MaxOpenReviews
+		levels[st.UserID] = st.Seniority
+	}
+	if len(caps) != 2 || caps["a"] != 3 || caps["b"] != 1 {
+		t.Fatalf("effective limits = %v, want map[a:3 b:1]", caps)
+	}
+	if levels["a"] != "" || levels["b"] != review.SenioritySenior {
+		t.Fatalf("seniority = %v, want map[a: b:SENIOR]", levels)
+	}
+}
+
+func testPRListReviewerStatsLastAssigned(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true), user("r3", true))
+	ctx := context.Background()
+
+	_, err := r.PRs.Create(ctx, review.PullRequest{
+		ID: "pr1", Title: "t", AuthorID: "a", Status: review.StatusOpen, CreatedAt: baseTime, ReviewerIDs: []string{"r1", "r2"},
+	},
+		review.PREvent{PRID: "pr1", Type: review.EventCreated, ActorID: "a", CreatedAt: baseTime},
+		review.PREvent{PRID: "pr1", Type: review.EventReviewerAssigned, NewReviewerID: "r2", CreatedAt: baseTime},
+		review.PREvent{PRID: "pr1", Type: review.EventReviewerAssigned, NewReviewerID: "r1", CreatedAt: baseTime},
+	)
+	noErr(t, err)
+
+	last := func() map[string]int64 {
+		t.Helper()
+		stats, err := r.PRs.ListReviewerStats(ctx, "backend")
+		noErr(t, err)
+		out := map[string]int64{}
+		for _, st := range stats {
+			out[st.UserID] = st.LastAssigned
+		}
+		return out
+	}
+
+	got := last()
+	if got["a"] != 0 || got["r3"] != 0 || got["r2"] == 0 || got["r1"] <= got["r2"] {
+		t.Fatalf("LastAssigned = %v, want a and r3 unassigned and r1 later than r2", got)
+	}
+
+	err = r.PRs.ReplaceReviewers(ctx, []review.ReviewerSwap{
+		{PRID: "pr1", Version: 1, OldReviewerID: "r1", NewReviewerID: "r3", NewReviewerTeam: "backend"},
+	},
+		review.PREvent{PRID: "pr1", Type: review.EventReviewerReassigned, OldReviewerID: "r1", NewReviewerID: "r3", CreatedAt: baseTime},
+		review.PREvent{PRID: "pr1", Type: review.EventReviewSubmitted, ActorID: "r2", CreatedAt: baseTime},
+	)
+	noErr(t, err)
+
+	after := last()
+	if after["r3"] <= got["r1"] || after["r1"] != got["r1"] || after["r2"] != got["r2"] {
+		t.Fatalf("LastAssigned after reassign = %v (before %v), want r3 latest and others unchanged", after, got)
+	}
+}
+
+func testPRListOpenByReviewers(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true), user("r3", true))
+	ctx := context.Background()
+
+	seedPR(t, r, "pr-late", "a", review.StatusOpen, baseTime.Add(time.Hour), "r2", "r3")
+	seedPR(t, r, "pr-early", "a", review.StatusOpen, baseTime, "r1", "r3")
+	seedPR(t, r, "pr-merged", "a", review.StatusMerged, baseTime, "r1")
+	seedPR(t, r, "pr-other", "a", review.StatusOpen, baseTime, "r3")
+
+	prs, err := r.PRs.ListOpenByReviewers(ctx, []string{"r1", "r2"})
+	noErr(t, err)
+	if len(prs) != 2 || prs[0].ID != "pr-early" || prs[1].ID != "pr-late" {
+		t.Fatalf("ListOpenByReviewers = %+v, want [pr-early pr-late]", prs)
+	}
+	if ids := sortedIDs(prs[0].ReviewerIDs); !equalIDs(ids, []string{"r1", "r3"}) {
+		t.Fatalf("pr-early reviewers = %v, want all reviewers [r1 r3]", ids)
+	}
+	if prs[0].Version != 1 || prs[0].AuthorID != "a" {
+		t.Fatalf("pr-early = %+v", prs[0])
+	}
+
+	none, err := r.PRs.ListOpenByReviewers(ctx, []string{"a"})
+	noErr(t, err)
+	if len(none) != 0 {
+		t.Fatalf("ListOpenByReviewers for author = %+v, want empty", none)
+	}
+}
+
+func testPRReplaceReviewers(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true), user("r3", true), user("r4", true))
+	ctx := context.Background()
+
+	seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime, "r1", "r2")
+	seedPR(t, r, "pr2", "a", review.StatusOpen, baseTime, "r1")
+
+	err := r.PRs.ReplaceReviewers(ctx, []review.ReviewerSwap{
+		{PRID: "pr1", Version: 1, OldReviewerID: "r1", NewReviewerID: "r3"},
+		{PRID: "pr1", Version: 1, OldReviewerID: "r2", NewReviewerID: "r4"},
+		{PRID: "pr2", Version: 1, OldReviewerID: "r1", NewReviewerID: "r2"},
+	},
+		review.PREvent{PRID: "pr1", Type: review.EventReviewerReassigned, OldReviewerID: "r1", NewReviewerID: "r3", CreatedAt: baseTime},
+		review.PREvent{PRID: "pr1", Type: review.EventReviewerReassigned, OldReviewerID: "r2", NewReviewerID: "r4", CreatedAt: baseTime},
+		review.PREvent{PRID: "pr2", Type: review.EventReviewerReassigned, OldReviewerID: "r1", NewReviewerID: "r2", CreatedAt: baseTime},
+	)
+	noErr(t, err)
+
+	pr1, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if ids := sortedIDs(pr1.ReviewerIDs); !equalIDs(ids, []string{"r3", "r4"}) || pr1.Version != 2 {
+		t.Fatalf("pr1 = reviewers %v version %d, want [r3 r4] and 2", ids, pr1.Version)
+	}
+	pr2, err := r.PRs.GetByID(ctx, "pr2")
+	noErr(t, err)
+	if !equalIDs(pr2.ReviewerIDs, []string{"r2"}) || pr2.Version != 2 {
+		t.Fatalf("pr2 = reviewers %v version %d, want [r2] and 2", pr2.ReviewerIDs, pr2.Version)
+	}
+
+	events, err := r.PRs.ListEvents(ctx, "pr1")
+	noErr(t, err)
+	if len(events) != 2 || events[1].OldReviewerID != "r2" || events[1].NewReviewerID != "r4" {
+		t.Fatalf("pr1 events = %+v", events)
+	}
+}
+
+func testPRReviewerTeams(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true))
+	seedTeam(t, r, "platform", user("p1", true), user("p2", true))
+	ctx := context.Background()
+
+	created, err := r.PRs.Create(ctx, review.PullRequest{
+		ID:            "pr1",
+		Title:         "title-pr1",
+		AuthorID:      "a",
+		Status:        review.StatusOpen,
+		CreatedAt:     baseTime,
+		ReviewerIDs:   []string{"r1", "p1"},
+		ReviewerTeams: map[string]string{"r1": "backend", "p1": "platform"},
+	})
+	noErr(t, err)
+	if created.ReviewerTeams["r1"] != "backend" || created.ReviewerTeams["p1"] != "platform" {
+		t.Fatalf("Create reviewer teams = %v", created.ReviewerTeams)
+	}
+
+	noErr(t, r.PRs.ReplaceReviewers(ctx, []review.ReviewerSwap{
+		{PRID: "pr1", Version: 1, OldReviewerID: "p1", NewReviewerID: "p2", NewReviewerTeam: "platform"},
+	}))
+	assigned, err := r.PRs.ListAssignedTo(ctx, "p2")
+	noErr(t, err)
+	if len(assigned) != 1 || len(assigned[0].ReviewerTeams) != 2 || assigned[0].ReviewerTeams["p2"] != "platform" {
+		t.Fatalf("ListAssignedTo reviewer teams = %+v", assigned)
+	}
+
+	_, err = r.Teams.Rename(ctx, "platform", "core")
+	noErr(t, err)
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.ReviewerTeams["p2"] != "core" {
+		t.Fatalf("reviewer teams after rename = %v, want p2 from core", got.ReviewerTeams)
+	}
+
+	got.ReviewerIDs = []string{"r1"}
+	got.ReviewerTeams = map[string]string{"r1": "backend", "p2": "core"}
+	updated, err := r.PRs.Update(ctx, got)
+	noErr(t, err)
+	if len(updated.ReviewerTeams) != 1 || updated.ReviewerTeams["r1"] != "backend" {
+		t.Fatalf("Update reviewer teams = %v, want only r1", updated.ReviewerTeams)
+	}
+
+	seedPR(t, r, "pr2", "a", review.StatusOpen, baseTime, "r1")
+	got, err = r.PRs.GetByID(ctx, "pr2")
+	noErr(t, err)
+	if len(got.ReviewerTeams) != 0 {
+		t.Fatalf("reviewer teams without source = %v, want none", got.ReviewerTeams)
+	}
+}
+
+func testPRRepositoryAndFiles(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true))
+	ctx := context.Background()
+
+	created, err := r.PRs.Create(ctx, review.PullRequest{
+		ID: "pr1", Title: "t", AuthorID: "a", Status: review.StatusOpen, CreatedAt: baseTime,
+		Repository: "billing", ChangedFiles: []string{"go.mod", "internal/api/handler.go"},
+		Labels: []string{"sql", "go"}, ReviewerIDs: []string{"r1"},
+	})
+	noErr(t, err)
+	if created.Repository != "billing" || !equalIDs(created.ChangedFiles, []string{"go.mod", "internal/api/handler.go"}) {
+		t.Fatalf("Create repository = %q, files = %v", created.Repository, created.ChangedFiles)
+	}
+	if !equalIDs(created.Labels, []string{"sql", "go"}) {
+		t.Fatalf("Create labels = %v, want [sql go] in order", created.Labels)
+	}
+
+	created.Title = "renamed"
+	created.Repository, created.ChangedFiles, created.Labels = "other", nil, nil
+	_, err = r.PRs.Update(ctx, created)
+	noErr(t, err)
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Repository != "billing" || !equalIDs(got.ChangedFiles, []string{"go.mod", "internal/api/handler.go"}) {
+		t.Fatalf("after Update repository = %q, files = %v; want unchanged", got.Repository, got.ChangedFiles)
+	}
+	if !equalIDs(got.Labels, []string{"sql", "go"}) {
+		t.Fatalf("after Update labels = %v, want unchanged", got.Labels)
+	}
+
+	open, err := r.PRs.ListOpenByReviewers(ctx, []string{"r1"})
+	noErr(t, err)
+	if len(open) != 1 || open[0].Repository != "billing" {
+		t.Fatalf("ListOpenByReviewers = %+v, want pr1 with repository", open)
+	}
+
+	plain := seedPR(t, r, "pr2", "a", review.StatusOpen, baseTime)
+	if plain.Repository != "" || plain.ChangedFiles != nil || plain.Labels != nil {
+		t.Fatalf("plain PR repository = %q, files = %v, labels = %v; want empty", plain.Repository, plain.ChangedFiles, plain.Labels)
+	}
+}
+
+func testPROwnershipRules(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("u1", true), user("u2", true))
+	ctx := context.Background()
+
+	rules, err := r.PRs.ListOwnershipRules(ctx, "billing")
+	noErr(t, err)
+	if len(rules) != 0 {
+		t.Fatalf("initial rules = %v, want none", rules)
+	}
+
+	want := []review.OwnershipRule{
+		{Pattern: "*", Owners: []string{"u1"}},
+		{Pattern: "/docs/"},
+		{Pattern: "*.go", Owners: []string{"u2", "u1"}},
+	}
+	noErr(t, r.PRs.SetOwnershipRules(ctx, "billing", want))
+	noErr(t, r.PRs.SetOwnershipRules(ctx, "web", []review.OwnershipRule{{Pattern: "*.ts", Owners: []string{"u2"}}}))
+
+	checkRules := func(repository string, want []review.OwnershipRule) {
+		t.Helper()
+		got, err := r.PRs.ListOwnershipRules(ctx, repository)
+		noErr(t, err)
+		if len(got) != len(want) {
+			t.Fatalf("%s rules = %+v, want %+v", repository, got, want)
+		}
+		for i := range want {
+			if got[i].Pattern != want[i].Pattern || !equalIDs(got[i].Owners, want[i].Owners) {
+				t.Fatalf("%s rule %d = %+v, want %+v", repository, i, got[i], want[i])
+			}
+		}
+	}
+	checkRules("billing", want)
+
+	err = r.PRs.SetOwnershipRules(ctx, "billing", []review.OwnershipRule{{Pattern: "*", Owners: []string{"nope"}}})
+	wantErr(t, err, review.ErrNotFound)
+	checkRules("billing", want)
+
+	noErr(t, r.PRs.SetOwnershipRules(ctx, "billing", []review.OwnershipRule{{Pattern: "/api/", Owners: []string{"u2"}}}))
+	checkRules("billing", []review.OwnershipRule{{Pattern: "/api/", Owners: []string{"u2"}}})
+	checkRules("web", []review.OwnershipRule{{Pattern: "*.ts", Owners: []string{"u2"}}})
+
+	noErr(t, r.PRs.SetOwnershipRules(ctx, "billing", nil))
+	checkRules("billing", nil)
+}
+
+func testPRReplaceReviewersConflict(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true), user("r1", true), user("r2", true), user("r3", true))
+	ctx := context.Background()
+
+	seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime, "r1")
+	pr2 := seedPR(t, r, "pr2", "a", review.StatusOpen, baseTime, "r1")
+	pr2.Title = "bumped"
+	_, err := r.PRs.Update(ctx, pr2)
+	noErr(t, err)
+
+	err = r.PRs.ReplaceReviewers(ctx, []review.ReviewerSwap{
+		{PRID: "pr1", Version: 1, OldReviewerID: "r1", NewReviewerID: "r2"},
+		{PRID: "pr2", Version: 1, OldReviewerID: "r1", NewReviewerID: "r3"},
+	}, review.PREvent{PRID: "pr1", Type: review.EventReviewerReassigned, CreatedAt: baseTime})
+	wantErr(t, err, review.ErrConflict)
+
+	pr1, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if !equalIDs(pr1.ReviewerIDs, []string{"r1"}) || pr1.Version != 1 {
+		t.Fatalf("pr1 changed despite conflict: %+v", pr1)
+	}
+	events, err := r.PRs.ListEvents(ctx, "pr1")
+	noErr(t, err)
+	if len(events) != 0 {
+		t.Fatalf("events written despite conflict: %+v", events)
+	}
+}
+
+func testTxCommit(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Teams.Create(ctx, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 2}); err != nil {
+			return err
+		}
+		_, err := r.Users.Create(ctx, review.User{ID: "u1", Team: "backend", Name: "Alice", IsActive: true})
+		return err
+	})
+	noErr(t, err)
+
+	_, err = r.Users.GetByID(ctx, "u1")
+	noErr(t, err)
+}
+
+func testTxRollback(t *testing.T, r Repos) {
+	seedTeam(t, r, "backend", user("a", true))
+	ctx := context.Background()
+	pr := seedPR(t, r, "pr1", "a", review.StatusOpen, baseTime)
+
+	boom := errors.New("boom")
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Teams.Create(ctx, review.Team{Name: "frontend", MinReviewers: 1, MaxReviewers: 2}); err != nil {
+			return err
+		}
+		if _, err := r.Users.Create(ctx, review.User{ID: "f1", Team: "frontend", Name: "F", IsActive: true}); err != nil {
+			return err
+		}
+		upd := pr
+		upd.Title = "changed"
+		if _, err := r.PRs.Update(ctx, upd, review.PREvent{PRID: "pr1", Type: review.EventClosed, CreatedAt: baseTime}); err != nil {
+			return err
+		}
+		return boom
+	})
+	wantErr(t, err, boom)
+
+	_, err = r.Teams.GetByName(ctx, "frontend")
+	wantErr(t, err, review.ErrNotFound)
+	_, err = r.Users.GetByID(ctx, "f1")
+	wantErr(t, err, review.ErrNotFound)
+
+	got, err := r.PRs.GetByID(ctx, "pr1")
+	noErr(t, err)
+	if got.Title != pr.Title || got.Version != pr.Version {
+		t.Fatalf("rolled back PR = %+v, want %+v", got, pr)
+	}
+	events, err := r.PRs.ListEvents(ctx, "pr1")
+	noErr(t, err)
+	if len(events) != 0 {
+		t.Fatalf("rolled back events = %+v, want none", events)
+	}
+}
+
+func testTxNested(t *testing.T, r Repos) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Teams.Create(ctx, review.Team{Name: "backend", MinReviewers: 1, MaxReviewers: 2}); err != nil {
+			return err
+		}
+		if err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			_, err := r.Users.Create(ctx, review.User{ID: "u1", Team: "backend", Name: "Alice", IsActive: true})
+			return err
+		}); err != nil {
+			return err
+		}
+		return boom
+	})
+	wantErr(t, err, boom)
+
+	_, err = r.Users.GetByID(ctx, "u1")
+	wantErr(t, err, review.ErrNotFound)
+	_, err = r.Teams.GetByName(ctx, "backend")
+	wantErr(t, err, review.ErrNotFound)
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	sqlitedrv "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type Config struct {
+	Path          string
+	MigrationsDir string
+}
+
+type DB struct {
+	log *slog.Logger
+	sql *sql.DB
+}
+
+func New(logg *slog.Logger, ctx context.Context, cfg Config) (*DB, error) {
+	if cfg.Path == "" {
+		cfg.Path = "pr-review.db"
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", cfg.Path)
+	logg.Info("opening sqlite database", "path", cfg.Path)
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		logg.Error("failed to open database", "error", err)
+		return nil, err
+	}
+
+	// SQLite допускает одного писателя; одно соединение исключает SQLITE_BUSY
+	// между транзакциями сервиса.
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		logg.Error("database ping failed", "error", err)
+		return nil, err
+	}
+
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		_ = db.Close()
+		logg.Error("sqlite.WithInstance failed", "error", err)
+		return nil, fmt.Errorf("sqlite.WithInstance: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(
+		fmt.Sprintf("file://%s", cfg.MigrationsDir),
+		"sqlite", driver,
+	)
+	if err != nil {
+		_ = db.Close()
+		logg.Error("failed to create migrate instance", "error", err)
+		return nil, fmt.Errorf("migrate.NewWithDatabaseInstance: %w", err)
+	}
+
+	logg.Info("running database migrations")
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		_ = db.Close()
+		logg.Error("migration up failed", "error", err)
+		return nil, fmt.Errorf("migrate up failed: %w", err)
+	}
+	logg.Info("database migrations completed")
+
+	return &DB{log: logg, sql: db}, nil
+}
+
+func (db *DB) Close() error {
+	db.log.Info("closing database connection")
+	return db.sql.Close()
+}
+
+// isConstraint сообщает, нарушено ли ограничение с указанным расширенным кодом SQLite.
+func isConstraint(err error, codes ...int) bool {
+	var sqliteErr *sqlitedrv.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	for _, c := range codes {
+		if sqliteErr.Code() == c {
+			return true
+		}
+	}
+	return false
+}
+
+func isUniqueViolation(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE)
+}
+
+// isForeignKeyViolation также учитывает SQLITE_CONSTRAINT_TRIGGER: так SQLite
+// сообщает о нарушении ON DELETE RESTRICT.
+func isForeignKeyViolation(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY, sqlite3.SQLITE_CONSTRAINT_TRIGGER)
+}
+
+// jsonArray кодирует список для json_each: так SQLite получает массив
+// одним параметром вместо динамического списка плейсхолдеров.
+func jsonArray(ids []string) string {
+	if ids == nil {
+		ids = []string{}
+	}
+	b, _ := json.Marshal(ids)
+	return string(b)
+}
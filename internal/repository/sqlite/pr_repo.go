@@ -0,0 +1,699 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/repository/sqltx"
+	"log/slog"
+	"time"
+)
+
+type PRRepo struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewPRRepo(db *DB, l *slog.Logger) *PRRepo {
+	return &PRRepo{db: db.sql, log: l}
+}
+
+func (r *PRRepo) Create(ctx context.Context, pr review.PullRequest, events ...review.PREvent) (review.PullRequest, error) {
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = time.Now().UTC()
+	}
+
+	r.log.Info("creating pull request", "pr_id", pr.ID, "title", pr.Title, "author", pr.AuthorID)
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err)
+		return review.PullRequest{}, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO pull_requests (pr_id, pr_title, author_id, pr_status, created_at, repository)
+         VALUES (?1, ?2, ?3, ?4, ?5, NULLIF(?6, ''))`,
+		pr.ID, pr.Title, pr.AuthorID, pr.Status, pr.CreatedAt, pr.Repository,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		if isUniqueViolation(err) {
+			return review.PullRequest{}, review.ErrPRExists
+		}
+		if isForeignKeyViolation(err) {
+			r.log.Warn("author not found for pull request", "pr_id", pr.ID, "author", pr.AuthorID)
+			return review.PullRequest{}, review.ErrNotFound
+		}
+		r.log.Error("failed to insert pull request", "error", err, "pr_id", pr.ID)
+		return review.PullRequest{}, err
+	}
+
+	for _, reviewerID := range pr.ReviewerIDs {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO pr_reviewers (pr_id, reviewer_id, source_team) VALUES (?1, ?2, NULLIF(?3, ''))`,
+			pr.ID, reviewerID, pr.ReviewerTeams[reviewerID],
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			if isForeignKeyViolation(err) {
+				return review.PullRequest{}, review.ErrNotFound
+			}
+			r.log.Error("failed to insert reviewer", "error", err, "pr_id", pr.ID, "reviewer_id", reviewerID)
+			return review.PullRequest{}, err
+		}
+	}
+
+	for i, path := range pr.ChangedFiles {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO pr_files (pr_id, file_path, position) VALUES (?1, ?2, ?3)`,
+			pr.ID, path, i,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			r.log.Error("failed to insert changed file", "error", err, "pr_id", pr.ID, "file_path", path)
+			return review.PullRequest{}, err
+		}
+	}
+
+	for i, label := range pr.Labels {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO pr_labels (pr_id, label, position) VALUES (?1, ?2, ?3)`,
+			pr.ID, label, i,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			r.log.Error("failed to insert label", "error", err, "pr_id", pr.ID, "label", label)
+			return review.PullRequest{}, err
+		}
+	}
+
+	if err := r.insertEvents(ctx, tx, events); err != nil {
+		_ = tx.Rollback()
+		return review.PullRequest{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "pr_id", pr.ID)
+		return review.PullRequest{}, err
+	}
+
+	r.log.Info("pull request created successfully", "pr_id", pr.ID)
+	return r.GetByID(ctx, pr.ID)
+}
+
+func (r *PRRepo) GetByID(ctx context.Context, id string) (review.PullRequest, error) {
+	var pr review.PullRequest
+	r.log.Info("fetching pull request by ID", "pr_id", id)
+
+	row := sqltx.Conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT pr_id, pr_title, author_id, pr_status, created_at, merged_at, closed_at, merge_forced, pr_version,
+		        COALESCE(repository, ''), COALESCE(closed_from, '')
+		 FROM pull_requests WHERE pr_id=?1`,
+		id,
+	)
+	err := row.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeForced, &pr.Version,
+		&pr.Repository, &pr.ClosedFrom)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.log.Warn("pull request not found", "pr_id", id)
+			return pr, review.ErrNotFound
+		}
+		r.log.Error("failed to scan pull request", "error", err, "pr_id", id)
+		return pr, err
+	}
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx, `SELECT reviewer_id, COALESCE(source_team, '') FROM pr_reviewers WHERE pr_id=?1`, pr.ID)
+	if err != nil {
+		r.log.Error("failed to fetch reviewers", "error", err, "pr_id", pr.ID)
+		return pr, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rid, team string
+		if err := rows.Scan(&rid, &team); err != nil {
+			return pr, err
+		}
+		addReviewer(&pr, rid, team)
+	}
+	if err := rows.Err(); err != nil {
+		return pr, err
+	}
+
+	pr.ChangedFiles, err = r.listOrdered(ctx, `SELECT file_path FROM pr_files WHERE pr_id=?1 ORDER BY position`, pr.ID)
+	if err != nil {
+		return pr, err
+	}
+	pr.Labels, err = r.listOrdered(ctx, `SELECT label FROM pr_labels WHERE pr_id=?1 ORDER BY position`, pr.ID)
+	if err != nil {
+		return pr, err
+	}
+
+	pr.Reviews, err = r.listReviews(ctx, pr.ID)
+	return pr, err
+}
+
+// listOrdered читает упорядоченный список строк PR: изменённые файлы или метки.
+func (r *PRRepo) listOrdered(ctx context.Context, query, prID string) ([]string, error) {
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx, query, prID)
+	if err != nil {
+		r.log.Error("failed to fetch pull request list", "error", err, "pr_id", prID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+func (r *PRRepo) listReviews(ctx context.Context, prID string) ([]review.Review, error) {
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT review_id, pr_id, reviewer_id, review_state, review_body, submitted_at
+		   FROM pr_reviews
+		  WHERE pr_id=?1
+		  ORDER BY submitted_at, review_id`,
+		prID,
+	)
+	if err != nil {
+		r.log.Error("failed to fetch reviews", "error", err, "pr_id", prID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []review.Review
+	for rows.Next() {
+		var rv review.Review
+		if err := rows.Scan(&rv.ID, &rv.PRID, &rv.ReviewerID, &rv.State, &rv.Body, &rv.SubmittedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *PRRepo) AddReview(ctx context.Context, rv review.Review, version int64, events ...review.PREvent) (review.Review, error) {
+	if rv.SubmittedAt.IsZero() {
+		rv.SubmittedAt = time.Now().UTC()
+	}
+
+	r.log.Info("adding review", "pr_id", rv.PRID, "reviewer_id", rv.ReviewerID, "state", rv.State)
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err, "pr_id", rv.PRID)
+		return review.Review{}, err
+	}
+
+	// Версия проверяется в той же транзакции, что и запись ревью: PR, который
+	// успели смержить или закрыть, ревью не получит.
+	res, err := tx.ExecContext(ctx,
+		`UPDATE pull_requests SET pr_version=pr_version+1 WHERE pr_id=?1 AND pr_version=?2`,
+		rv.PRID, version,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to bump pull request version", "error", err, "pr_id", rv.PRID)
+		return review.Review{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		_ = tx.Rollback()
+		return review.Review{}, r.missingOrConflict(ctx, rv.PRID, version)
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO pr_reviews (pr_id, reviewer_id, review_state, review_body, submitted_at)
+		 VALUES (?1, ?2, ?3, ?4, ?5)
+		 RETURNING review_id`,
+		rv.PRID, rv.ReviewerID, rv.State, rv.Body, rv.SubmittedAt,
+	).Scan(&rv.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		if isForeignKeyViolation(err) {
+			return review.Review{}, review.ErrNotFound
+		}
+		r.log.Error("failed to insert review", "error", err, "pr_id", rv.PRID)
+		return review.Review{}, err
+	}
+
+	if err := r.insertEvents(ctx, tx, events); err != nil {
+		_ = tx.Rollback()
+		return review.Review{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "pr_id", rv.PRID)
+		return review.Review{}, err
+	}
+
+	return rv, nil
+}
+
+// missingOrConflict определяет причину неудачного compare-and-swap.
+func (r *PRRepo) missingOrConflict(ctx context.Context, prID string, version int64) error {
+	var exists bool
+	err := sqltx.Conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pr_id=?1)`, prID,
+	).Scan(&exists)
+	if err != nil {
+		r.log.Error("failed to check pull request existence", "error", err, "pr_id", prID)
+		return err
+	}
+	if !exists {
+		r.log.Warn("pull request not found for update", "pr_id", prID)
+		return review.ErrNotFound
+	}
+	r.log.Warn("pull request version conflict", "pr_id", prID, "version", version)
+	return review.ErrConflict
+}
+
+func (r *PRRepo) insertEvents(ctx context.Context, tx sqltx.Querier, events []review.PREvent) error {
+	for _, ev := range events {
+		if ev.CreatedAt.IsZero() {
+			ev.CreatedAt = time.Now().UTC()
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pr_events (pr_id, event_type, actor_id, old_reviewer_id, new_reviewer_id, details, created_at)
+			 VALUES (?1, ?2, ?3, NULLIF(?4, ''), NULLIF(?5, ''), ?6, ?7)`,
+			ev.PRID, ev.Type, ev.ActorID, ev.OldReviewerID, ev.NewReviewerID, ev.Details, ev.CreatedAt,
+		)
+		if err != nil {
+			r.log.Error("failed to insert PR event", "error", err, "pr_id", ev.PRID, "event_type", ev.Type)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PRRepo) ListEvents(ctx context.Context, prID string) ([]review.PREvent, error) {
+	r.log.Info("listing PR events", "pr_id", prID)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT event_id, pr_id, event_type, actor_id,
+		        COALESCE(old_reviewer_id, ''), COALESCE(new_reviewer_id, ''), details, created_at
+		   FROM pr_events
+		  WHERE pr_id=?1
+		  ORDER BY event_id`,
+		prID,
+	)
+	if err != nil {
+		r.log.Error("failed to query PR events", "error", err, "pr_id", prID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []review.PREvent
+	for rows.Next() {
+		var ev review.PREvent
+		if err := rows.Scan(&ev.ID, &ev.PRID, &ev.Type, &ev.ActorID,
+			&ev.OldReviewerID, &ev.NewReviewerID, &ev.Details, &ev.CreatedAt); err != nil {
+			r.log.Error("failed to scan PR event", "error", err)
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+func (r *PRRepo) Update(ctx context.Context, pr review.PullRequest, events ...review.PREvent) (review.PullRequest, error) {
+	r.log.Info("updating pull request", "pr_id", pr.ID)
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err, "pr_id", pr.ID)
+		return review.PullRequest{}, err
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE pull_requests
+		    SET pr_title=?1, pr_status=?2, merged_at=?3, closed_at=?4, merge_forced=?5,
+		        closed_from=NULLIF(?8, ''), pr_version=pr_version+1
+		  WHERE pr_id=?6 AND pr_version=?7`,
+		pr.Title, pr.Status, pr.MergedAt, pr.ClosedAt, pr.MergeForced, pr.ID, pr.Version, pr.ClosedFrom,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to update pull request", "error", err, "pr_id", pr.ID)
+		return review.PullRequest{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		_ = tx.Rollback()
+		return review.PullRequest{}, r.missingOrConflict(ctx, pr.ID, pr.Version)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM pr_reviewers WHERE pr_id=?1`, pr.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to delete old reviewers", "error", err, "pr_id", pr.ID)
+		return review.PullRequest{}, err
+	}
+
+	for _, rid := range pr.ReviewerIDs {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO pr_reviewers (pr_id, reviewer_id, source_team) VALUES (?1, ?2, NULLIF(?3, ''))`,
+			pr.ID, rid, pr.ReviewerTeams[rid],
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			if isForeignKeyViolation(err) {
+				return review.PullRequest{}, review.ErrNotFound
+			}
+			r.log.Error("failed to insert reviewer", "error", err, "pr_id", pr.ID, "reviewer_id", rid)
+			return review.PullRequest{}, err
+		}
+	}
+
+	if err := r.insertEvents(ctx, tx, events); err != nil {
+		_ = tx.Rollback()
+		return review.PullRequest{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "pr_id", pr.ID)
+		return review.PullRequest{}, err
+	}
+
+	r.log.Info("pull request updated successfully", "pr_id", pr.ID)
+	return r.GetByID(ctx, pr.ID)
+}
+
+func (r *PRRepo) ListAssignedTo(ctx context.Context, userID string) ([]review.PullRequest, error) {
+	r.log.Info("listing pull requests assigned to user", "user_id", userID)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT p.pr_id
+		   FROM pull_requests p
+		   JOIN pr_reviewers prr ON p.pr_id = prr.pr_id
+		  WHERE prr.reviewer_id = ?1
+		  ORDER BY p.created_at, p.pr_id`,
+		userID,
+	)
+	if err != nil {
+		r.log.Error("failed to query assigned PRs", "error", err, "user_id", userID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	var result []review.PullRequest
+	for _, id := range ids {
+		pr, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pr)
+	}
+
+	return result, nil
+}
+
+func (r *PRRepo) ListReviewerStats(ctx context.Context, team string) ([]review.ReviewerStats, error) {
+	r.log.Info("listing reviewer stats", "team", team)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT u.user_id, u.user_name, u.team_name, COUNT(p.pr_id),
+		        COALESCE(u.max_open_reviews, t.max_open_reviews, 0), COALESCE(u.seniority, ''),
+		        COALESCE((SELECT group_concat(tag, ',') FROM user_tags ut WHERE ut.user_id = u.user_id), ''),
+		        COALESCE((SELECT MAX(e.event_id) FROM pr_events e
+		                   WHERE e.new_reviewer_id = u.user_id
+		                     AND e.event_type IN ('REVIEWER_ASSIGNED', 'REVIEWER_REASSIGNED')), 0)
+		   FROM users u
+		   JOIN teams t ON t.team_name = u.team_name
+		   LEFT JOIN pr_reviewers prr ON prr.reviewer_id = u.user_id
+		   LEFT JOIN pull_requests p ON p.pr_id = prr.pr_id AND p.pr_status = 'OPEN'
+		  WHERE u.team_name = ?1 AND u.is_active = true
+		  GROUP BY u.user_id, u.user_name, u.team_name, u.max_open_reviews, t.max_open_reviews, u.seniority
+		  ORDER BY COUNT(p.pr_id) ASC, u.user_id ASC`,
+		team,
+	)
+	if err != nil {
+		r.log.Error("failed to query reviewer stats", "error", err, "team", team)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []review.ReviewerStats
+	for rows.Next() {
+		var (
+			s    review.ReviewerStats
+			tags string
+		)
+		if err := rows.Scan(&s.UserID, &s.Username, &s.TeamName, &s.AssignedOpenPRs, &s.MaxOpenReviews, &s.Seniority, &tags, &s.LastAssigned); err != nil {
+			r.log.Error("failed to scan reviewer stats", "error", err)
+			return nil, err
+		}
+		s.Tags = splitTags(tags)
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *PRRepo) ListOpenByReviewers(ctx context.Context, reviewerIDs []string) ([]review.PullRequest, error) {
+	r.log.Info("listing open pull requests by reviewers", "reviewers_count", len(reviewerIDs))
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT p.pr_id, p.pr_title, p.author_id, p.pr_status, p.created_at, p.merged_at, p.closed_at, p.merge_forced, p.pr_version,
+		        COALESCE(p.repository, '')
+		   FROM pull_requests p
+		  WHERE p.pr_status = 'OPEN'
+		    AND EXISTS (SELECT 1 FROM pr_reviewers prr
+		                 WHERE prr.pr_id = p.pr_id AND prr.reviewer_id IN (SELECT value FROM json_each(?1)))
+		  ORDER BY p.created_at, p.pr_id`,
+		jsonArray(reviewerIDs),
+	)
+	if err != nil {
+		r.log.Error("failed to query open PRs by reviewers", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		result []review.PullRequest
+		ids    []string
+	)
+	for rows.Next() {
+		var pr review.PullRequest
+		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeForced, &pr.Version,
+			&pr.Repository); err != nil {
+			r.log.Error("failed to scan pull request", "error", err)
+			return nil, err
+		}
+		result = append(result, pr)
+		ids = append(ids, pr.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	revRows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT pr_id, reviewer_id, COALESCE(source_team, '') FROM pr_reviewers WHERE pr_id IN (SELECT value FROM json_each(?1))`, jsonArray(ids),
+	)
+	if err != nil {
+		r.log.Error("failed to fetch reviewers", "error", err)
+		return nil, err
+	}
+	defer revRows.Close()
+
+	index := make(map[string]int, len(result))
+	for i, pr := range result {
+		index[pr.ID] = i
+	}
+	for revRows.Next() {
+		var prID, rid, team string
+		if err := revRows.Scan(&prID, &rid, &team); err != nil {
+			return nil, err
+		}
+		addReviewer(&result[index[prID]], rid, team)
+	}
+
+	return result, revRows.Err()
+}
+
+// ReplaceReviewers в SQLite выполняется построчно: база локальная, и все
+// запросы идут в одной транзакции без сетевых задержек.
+func (r *PRRepo) ReplaceReviewers(ctx context.Context, swaps []review.ReviewerSwap, events ...review.PREvent) error {
+	r.log.Info("replacing reviewers", "swaps_count", len(swaps))
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+
+	bumped := map[string]struct{}{}
+	for _, sw := range swaps {
+		if _, ok := bumped[sw.PRID]; !ok {
+			res, err := tx.ExecContext(ctx,
+				`UPDATE pull_requests SET pr_version=pr_version+1 WHERE pr_id=?1 AND pr_version=?2`,
+				sw.PRID, sw.Version,
+			)
+			if err != nil {
+				_ = tx.Rollback()
+				r.log.Error("failed to bump pull request version", "error", err, "pr_id", sw.PRID)
+				return err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				_ = tx.Rollback()
+				r.log.Warn("pull request version conflict in batch", "pr_id", sw.PRID, "version", sw.Version)
+				return review.ErrConflict
+			}
+			bumped[sw.PRID] = struct{}{}
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`UPDATE pr_reviewers SET reviewer_id=?1, source_team=NULLIF(?4, '') WHERE pr_id=?2 AND reviewer_id=?3`,
+			sw.NewReviewerID, sw.PRID, sw.OldReviewerID, sw.NewReviewerTeam,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			if isForeignKeyViolation(err) {
+				return review.ErrNotFound
+			}
+			r.log.Error("failed to replace reviewer", "error", err, "pr_id", sw.PRID)
+			return err
+		}
+	}
+
+	if err := r.insertEvents(ctx, tx, events); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err)
+		return err
+	}
+
+	r.log.Info("reviewers replaced successfully", "prs_count", len(bumped), "swaps_count", len(swaps))
+	return nil
+}
+
+func (r *PRRepo) SetOwnershipRules(ctx context.Context, repository string, rules []review.OwnershipRule) error {
+	r.log.Info("setting ownership rules", "repository", repository, "rules_count", len(rules))
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM code_owner_rules WHERE repository=?1`, repository); err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to delete ownership rules", "error", err, "repository", repository)
+		return err
+	}
+
+	for i, rule := range rules {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO code_owner_rules (repository, position, pattern) VALUES (?1, ?2, ?3)`,
+			repository, i, rule.Pattern,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			r.log.Error("failed to insert ownership rule", "error", err, "repository", repository, "pattern", rule.Pattern)
+			return err
+		}
+
+		for j, owner := range rule.Owners {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO code_owner_rule_owners (repository, rule_position, user_id, position) VALUES (?1, ?2, ?3, ?4)`,
+				repository, i, owner, j,
+			)
+			if err != nil {
+				_ = tx.Rollback()
+				if isForeignKeyViolation(err) {
+					r.log.Warn("code owner not found", "repository", repository, "user_id", owner)
+					return review.ErrNotFound
+				}
+				r.log.Error("failed to insert code owner", "error", err, "repository", repository, "user_id", owner)
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "repository", repository)
+		return err
+	}
+	return nil
+}
+
+func (r *PRRepo) ListOwnershipRules(ctx context.Context, repository string) ([]review.OwnershipRule, error) {
+	r.log.Info("listing ownership rules", "repository", repository)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT cr.position, cr.pattern, COALESCE(o.user_id, '')
+		   FROM code_owner_rules cr
+		   LEFT JOIN code_owner_rule_owners o
+		     ON o.repository = cr.repository AND o.rule_position = cr.position
+		  WHERE cr.repository=?1
+		  ORDER BY cr.position, o.position`,
+		repository,
+	)
+	if err != nil {
+		r.log.Error("failed to query ownership rules", "error", err, "repository", repository)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOwnershipRules(rows)
+}
+
+// scanOwnershipRules собирает правила из строк (position, pattern, owner),
+// упорядоченных по позиции правила; пустой owner — правило без владельцев.
+func scanOwnershipRules(rows *sql.Rows) ([]review.OwnershipRule, error) {
+	var (
+		rules []review.OwnershipRule
+		last  = -1
+	)
+	for rows.Next() {
+		var (
+			pos            int
+			pattern, owner string
+		)
+		if err := rows.Scan(&pos, &pattern, &owner); err != nil {
+			return nil, err
+		}
+		if pos != last {
+			rules = append(rules, review.OwnershipRule{Pattern: pattern})
+			last = pos
+		}
+		if owner != "" {
+			rules[len(rules)-1].Owners = append(rules[len(rules)-1].Owners, owner)
+		}
+	}
+	return rules, rows.Err()
+}
+
+// addReviewer добавляет ревьювера в PR; пустая команда не сохраняется в
+// ReviewerTeams.
+func addReviewer(pr *review.PullRequest, reviewerID, team string) {
+	pr.ReviewerIDs = append(pr.ReviewerIDs, reviewerID)
+	if team == "" {
+		return
+	}
+	if pr.ReviewerTeams == nil {
+		pr.ReviewerTeams = map[string]string{}
+	}
+	pr.ReviewerTeams[reviewerID] = team
+}
@@ -0,0 +1,40 @@
+package sqlite_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/zapevnik/pr-review-service/internal/repository/repotest"
+	"github.com/zapevnik/pr-review-service/internal/repository/sqlite"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, newRepos)
+}
+
+func BenchmarkDeactivateTeamUsers(b *testing.B) {
+	repotest.BenchmarkDeactivateTeamUsers(b, newRepos)
+}
+
+func newRepos(tb testing.TB) repotest.Repos {
+	logg := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := sqlite.New(logg, context.Background(), sqlite.Config{
+		Path:          filepath.Join(tb.TempDir(), "test.db"),
+		MigrationsDir: "../../../migrations/sqlite",
+	})
+	if err != nil {
+		tb.Fatalf("open sqlite: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+
+	return repotest.Repos{
+		PRs:   sqlite.NewPRRepo(db, logg),
+		Users: sqlite.NewUserRepo(db, logg),
+		Teams: sqlite.NewTeamRepo(db, logg),
+		Tx:    sqlite.NewTxManager(db, logg),
+	}
+}
@@ -0,0 +1,289 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/repository/sqltx"
+)
+
+// userColumns — поля пользователя для выборки из users; теги собираются
+// подзапросом в строку через запятую и разбираются splitTags.
+const userColumns = `user_id, user_name, is_active, COALESCE(team_name, ''), COALESCE(max_open_reviews, 0), COALESCE(seniority, ''),
+	COALESCE((SELECT group_concat(tag, ',') FROM user_tags ut WHERE ut.user_id = users.user_id), '')`
+
+type UserRepo struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewUserRepo(db *DB, l *slog.Logger) *UserRepo {
+	return &UserRepo{db: db.sql, log: l}
+}
+
+func (r *UserRepo) Create(ctx context.Context, u review.User) (review.User, error) {
+	r.log.Info("creating user", "user_id", u.ID, "username", u.Name, "team", u.Team)
+
+	_, err := sqltx.Conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO users (user_id, user_name, is_active, team_name, max_open_reviews, seniority)
+		 VALUES (?1, ?2, ?3, NULLIF(?4, ''), NULLIF(?5, 0), NULLIF(?6, ''))`,
+		u.ID, u.Name, u.IsActive, u.Team, u.MaxOpenReviews, u.Seniority,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			r.log.Warn("team not found for user", "user_id", u.ID, "team", u.Team)
+			return review.User{}, review.ErrNotFound
+		}
+		r.log.Error("failed to insert user", "error", err, "user_id", u.ID)
+		return review.User{}, err
+	}
+	r.log.Info("user created successfully", "user_id", u.ID)
+	return u, nil
+}
+
+func (r *UserRepo) Update(ctx context.Context, u review.User) (review.User, error) {
+	r.log.Info("updating user", "user_id", u.ID, "team", u.Team)
+
+	res, err := sqltx.Conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE users SET user_name=?1, is_active=?2, team_name=NULLIF(?3, ''), max_open_reviews=NULLIF(?4, 0),
+		        seniority=NULLIF(?5, '')
+		 WHERE user_id=?6`,
+		u.Name, u.IsActive, u.Team, u.MaxOpenReviews, u.Seniority, u.ID,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			r.log.Warn("team not found for user", "user_id", u.ID, "team", u.Team)
+			return review.User{}, review.ErrNotFound
+		}
+		r.log.Error("failed to update user", "error", err, "user_id", u.ID)
+		return review.User{}, err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		r.log.Warn("user not found for update", "user_id", u.ID)
+		return review.User{}, review.ErrNotFound
+	}
+
+	return r.GetByID(ctx, u.ID)
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, userID string) (review.User, error) {
+	r.log.Info("fetching user by ID", "user_id", userID)
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id=?1`
+	var (
+		u    review.User
+		tags string
+	)
+	err := sqltx.Conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&u.ID, &u.Name, &u.IsActive, &u.Team, &u.MaxOpenReviews, &u.Seniority, &tags)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.log.Warn("user not found", "user_id", userID)
+			return review.User{}, review.ErrNotFound
+		}
+		r.log.Error("failed to scan user", "error", err, "user_id", userID)
+		return review.User{}, err
+	}
+	u.Tags = splitTags(tags)
+	return u, nil
+}
+
+func (r *UserRepo) ListByIDs(ctx context.Context, ids []string) ([]review.User, error) {
+	r.log.Info("listing users by IDs", "users_count", len(ids))
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id IN (SELECT value FROM json_each(?1)) ORDER BY user_id`
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx, query, jsonArray(ids))
+	if err != nil {
+		r.log.Error("failed to query users by IDs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []review.User
+	for rows.Next() {
+		var (
+			u    review.User
+			tags string
+		)
+		if err := rows.Scan(&u.ID, &u.Name, &u.IsActive, &u.Team, &u.MaxOpenReviews, &u.Seniority, &tags); err != nil {
+			return nil, err
+		}
+		u.Tags = splitTags(tags)
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepo) ListActiveByTeam(ctx context.Context, teamName string) ([]review.User, error) {
+	r.log.Info("listing active users by team", "team", teamName)
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE team_name=?1 AND is_active=true ORDER BY user_id`
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx, query, teamName)
+	if err != nil {
+		r.log.Error("failed to query active users", "error", err, "team", teamName)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []review.User
+	for rows.Next() {
+		var (
+			u    review.User
+			tags string
+		)
+		if err := rows.Scan(&u.ID, &u.Name, &u.IsActive, &u.Team, &u.MaxOpenReviews, &u.Seniority, &tags); err != nil {
+			return nil, err
+		}
+		u.Tags = splitTags(tags)
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepo) ListByTeam(ctx context.Context, teamName string) ([]review.User, error) {
+	r.log.Info("listing all users by team", "team", teamName)
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE team_name=?1 ORDER BY user_id`
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx, query, teamName)
+	if err != nil {
+		r.log.Error("failed to query users by team", "error", err, "team", teamName)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []review.User
+	for rows.Next() {
+		var (
+			u    review.User
+			tags string
+		)
+		if err := rows.Scan(&u.ID, &u.Name, &u.IsActive, &u.Team, &u.MaxOpenReviews, &u.Seniority, &tags); err != nil {
+			return nil, err
+		}
+		u.Tags = splitTags(tags)
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepo) SetActive(ctx context.Context, userIDs []string, active bool) error {
+	r.log.Info("setting users active flag", "users_count", len(userIDs), "is_active", active)
+
+	_, err := sqltx.Conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE users SET is_active=?1 WHERE user_id IN (SELECT value FROM json_each(?2))`, active, jsonArray(userIDs),
+	)
+	if err != nil {
+		r.log.Error("failed to set users active flag", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UserRepo) AddAbsence(ctx context.Context, a review.Absence) (review.Absence, error) {
+	r.log.Info("adding absence", "user_id", a.UserID, "starts_at", a.StartsAt, "ends_at", a.EndsAt)
+
+	err := sqltx.Conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO user_absences (user_id, starts_at, ends_at, reason) VALUES (?1, ?2, ?3, ?4)
+		 RETURNING absence_id`,
+		a.UserID, a.StartsAt.UTC(), a.EndsAt.UTC(), a.Reason,
+	).Scan(&a.ID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			r.log.Warn("user not found for absence", "user_id", a.UserID)
+			return review.Absence{}, review.ErrNotFound
+		}
+		r.log.Error("failed to insert absence", "error", err, "user_id", a.UserID)
+		return review.Absence{}, err
+	}
+	return a, nil
+}
+
+func (r *UserRepo) ListTeamAbsences(ctx context.Context, teamName string, from time.Time) ([]review.Absence, error) {
+	r.log.Info("listing team absences", "team", teamName, "from", from)
+
+	rows, err := sqltx.Conn(ctx, r.db).QueryContext(ctx,
+		`SELECT a.absence_id, a.user_id, a.starts_at, a.ends_at, a.reason
+		   FROM user_absences a
+		   JOIN users u ON u.user_id = a.user_id
+		  WHERE u.team_name = ?1 AND a.ends_at > ?2
+		  ORDER BY a.starts_at, a.absence_id`,
+		teamName, from.UTC(),
+	)
+	if err != nil {
+		r.log.Error("failed to query team absences", "error", err, "team", teamName)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []review.Absence
+	for rows.Next() {
+		var a review.Absence
+		if err := rows.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason); err != nil {
+			return nil, err
+		}
+		a.StartsAt, a.EndsAt = a.StartsAt.UTC(), a.EndsAt.UTC()
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+func (r *UserRepo) SetTags(ctx context.Context, userID string, tags []string) error {
+	r.log.Info("setting user tags", "user_id", userID, "tags", tags)
+
+	tx, err := sqltx.Begin(ctx, r.db)
+	if err != nil {
+		r.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM users WHERE user_id=?1)`, userID).Scan(&exists)
+	if err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to check user existence", "error", err, "user_id", userID)
+		return err
+	}
+	if !exists {
+		_ = tx.Rollback()
+		r.log.Warn("user not found for tags", "user_id", userID)
+		return review.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_tags WHERE user_id=?1`, userID); err != nil {
+		_ = tx.Rollback()
+		r.log.Error("failed to delete user tags", "error", err, "user_id", userID)
+		return err
+	}
+
+	for _, tag := range tags {
+		_, err := tx.ExecContext(ctx, `INSERT INTO user_tags (user_id, tag) VALUES (?1, ?2)`, userID, tag)
+		if err != nil {
+			_ = tx.Rollback()
+			r.log.Error("failed to insert user tag", "error", err, "user_id", userID, "tag", tag)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.Error("failed to commit transaction", "error", err, "user_id", userID)
+		return err
+	}
+	return nil
+}
+
+// splitTags разбирает теги, собранные подзапросом userColumns.
+func splitTags(s string) []string {
+	if s == "" {
+		return nil
+	}
+	tags := strings.Split(s, ",")
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,96 @@
+// Package sqltx — общие для SQL-хранилищ транзакции: репозитории postgres и
+// sqlite берут соединение через Conn и Begin, а TxManager объединяет их
+// вызовы в одну *sql.Tx.
+package sqltx
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+)
+
+// Querier — общее подмножество *sql.DB и *sql.Tx, которым пользуются репозитории.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// Conn возвращает транзакцию, открытую TxManager.WithinTx, или пул соединений.
+func Conn(ctx context.Context, db *sql.DB) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// LocalTx — транзакция одного метода репозитория. Внутри TxManager.WithinTx
+// она работает во внешней транзакции и оставляет Commit/Rollback ей.
+type LocalTx struct {
+	Querier
+	tx *sql.Tx
+}
+
+// Begin открывает LocalTx.
+func Begin(ctx context.Context, db *sql.DB) (*LocalTx, error) {
+	if outer, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &LocalTx{Querier: outer}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalTx{Querier: tx, tx: tx}, nil
+}
+
+func (t *LocalTx) Commit() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+func (t *LocalTx) Rollback() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Rollback()
+}
+
+// TxManager реализует review.TxManager поверх *sql.Tx: репозитории,
+// вызванные с контекстом из WithinTx, выполняют запросы в одной транзакции.
+type TxManager struct {
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewTxManager(db *sql.DB, l *slog.Logger) *TxManager {
+	return &TxManager{db: db, log: l}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to begin transaction", "error", err)
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			m.log.Error("failed to rollback transaction", "error", rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.log.Error("failed to commit transaction", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package req
+
+type OwnershipRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type SetCodeOwners struct {
+	Repository string          `json:"repository"`
+	Rules      []OwnershipRule `json:"rules"`
+}
@@ -1,16 +1,60 @@
 package req
 
 type CreatePR struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	ReviewersCount  *int     `json:"reviewers_count,omitempty"`
+	Draft           bool     `json:"draft"`
+	Repository      string   `json:"repository,omitempty"`
+	ChangedFiles    []string `json:"changed_files,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+}
+
+// PreviewAssignment — параметры PR для предпросмотра подбора ревьюверов;
+// pull_request_id необязателен и попадает только в логи.
+type PreviewAssignment struct {
+	PullRequestID   string   `json:"pull_request_id,omitempty"`
+	PullRequestName string   `json:"pull_request_name,omitempty"`
+	AuthorID        string   `json:"author_id"`
+	ReviewersCount  *int     `json:"reviewers_count,omitempty"`
+	Repository      string   `json:"repository,omitempty"`
+	ChangedFiles    []string `json:"changed_files,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+}
+
+type ReadyPR struct {
 	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	ReviewersCount  *int   `json:"reviewers_count,omitempty"`
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
 }
 
 type MergePR struct {
-	PullRequestID string `json:"pull_request_id"`
+	PullRequestID   string `json:"pull_request_id"`
+	Force           bool   `json:"force"`
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+type ClosePR struct {
+	PullRequestID   string `json:"pull_request_id"`
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+type ReopenPR struct {
+	PullRequestID   string `json:"pull_request_id"`
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
 }
 
 type ReassignReviewer struct {
-	PullRequestID string `json:"pull_request_id"`
-	OldUserID     string `json:"old_user_id"`
+	PullRequestID   string `json:"pull_request_id"`
+	OldUserID       string `json:"old_user_id"`
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+type SubmitReview struct {
+	PullRequestID   string `json:"pull_request_id"`
+	ReviewerID      string `json:"reviewer_id"`
+	State           string `json:"state"`
+	Body            string `json:"body"`
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
 }
@@ -0,0 +1,12 @@
+package req
+
+type ReviewerRule struct {
+	Type       string `json:"type"`
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+}
+
+type SetReviewerRules struct {
+	TeamName string         `json:"team_name"`
+	Rules    []ReviewerRule `json:"rules"`
+}
@@ -1,12 +1,71 @@
 package req
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews int    `json:"max_open_reviews,omitempty"`
+	Seniority      string `json:"seniority,omitempty"`
 }
 
 type TeamAdd struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName       string       `json:"team_name"`
+	Members        []TeamMember `json:"members"`
+	MinReviewers   *int         `json:"min_reviewers,omitempty"`
+	MaxReviewers   *int         `json:"max_reviewers,omitempty"`
+	MaxOpenReviews int          `json:"max_open_reviews,omitempty"`
+}
+
+type TeamAddMember struct {
+	TeamName       string `json:"team_name"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews int    `json:"max_open_reviews,omitempty"`
+	Seniority      string `json:"seniority,omitempty"`
+}
+
+type TeamRemoveMember struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type TeamRename struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type TeamDelete struct {
+	TeamName string `json:"team_name"`
+}
+
+type TeamDeactivateUsers struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type TeamSetMaxOpenReviews struct {
+	TeamName       string `json:"team_name"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+type TeamSetReviewersLimits struct {
+	TeamName     string `json:"team_name"`
+	MinReviewers *int   `json:"min_reviewers"`
+	MaxReviewers *int   `json:"max_reviewers"`
+}
+
+type TeamSetFallbacks struct {
+	TeamName      string   `json:"team_name"`
+	FallbackTeams []string `json:"fallback_teams"`
+}
+
+type RoleQuota struct {
+	Seniority string `json:"seniority"`
+	Count     int    `json:"count"`
+}
+
+type TeamSetRoleQuotas struct {
+	TeamName   string      `json:"team_name"`
+	RoleQuotas []RoleQuota `json:"role_quotas"`
 }
@@ -1,6 +1,36 @@
 package req
 
+import "time"
+
 type SetIsActive struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
+	Reassign bool   `json:"reassign,omitempty"`
+}
+
+type MoveTeam struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type AddAbsence struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type SetMaxOpenReviews struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+type SetTags struct {
+	UserID string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+type SetSeniority struct {
+	UserID    string `json:"user_id"`
+	Seniority string `json:"seniority"`
 }
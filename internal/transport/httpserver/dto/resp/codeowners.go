@@ -0,0 +1,11 @@
+package resp
+
+type OwnershipRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type CodeOwners struct {
+	Repository string          `json:"repository"`
+	Rules      []OwnershipRule `json:"rules"`
+}
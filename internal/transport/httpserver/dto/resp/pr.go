@@ -3,13 +3,29 @@ package resp
 import "time"
 
 type PullRequest struct {
-	PullRequestID     string     `json:"pull_request_id"`
-	PullRequestName   string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            string     `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         time.Time  `json:"created_at,omitempty"`
-	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	PullRequestID     string            `json:"pull_request_id"`
+	PullRequestName   string            `json:"pull_request_name"`
+	AuthorID          string            `json:"author_id"`
+	Status            string            `json:"status"`
+	AssignedReviewers []string          `json:"assigned_reviewers"`
+	Reviewers         []ReviewerState   `json:"reviewers"`
+	ReviewerTeams     map[string]string `json:"reviewer_teams,omitempty"`
+	Repository        string            `json:"repository,omitempty"`
+	ChangedFiles      []string          `json:"changed_files,omitempty"`
+	Labels            []string          `json:"labels,omitempty"`
+	UnmatchedLabels   []string          `json:"unmatched_labels,omitempty"`
+	CreatedAt         time.Time         `json:"created_at,omitempty"`
+	MergedAt          *time.Time        `json:"merged_at,omitempty"`
+	ClosedAt          *time.Time        `json:"closed_at,omitempty"`
+	MergeForced       bool              `json:"merge_forced,omitempty"`
+	Version           int64             `json:"version"`
+}
+
+type ReviewerState struct {
+	UserID      string     `json:"user_id"`
+	State       string     `json:"state"`
+	Body        string     `json:"body,omitempty"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
 }
 
 type PullRequestShort struct {
@@ -27,12 +43,80 @@ type MergePR struct {
 	PR PullRequest `json:"pr"`
 }
 
+type ReadyPR struct {
+	PR PullRequest `json:"pr"`
+}
+
+type ClosePR struct {
+	PR PullRequest `json:"pr"`
+}
+
+type ReopenPR struct {
+	PR PullRequest `json:"pr"`
+}
+
+type SubmitReview struct {
+	PR PullRequest `json:"pr"`
+}
+
 type ReassignReviewer struct {
 	PR         PullRequest `json:"pr"`
 	ReplacedBy string      `json:"replaced_by"`
 }
 
+type Reassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id"`
+}
+
+type ReassignmentFailure struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Reason        string `json:"reason"`
+}
+
+// ReassignmentReport — результат перераспределения открытых ревью пользователя.
+type ReassignmentReport struct {
+	Reassigned []Reassignment        `json:"reassigned"`
+	Failed     []ReassignmentFailure `json:"failed"`
+}
+
+type PREvent struct {
+	EventID       int64     `json:"event_id"`
+	Type          string    `json:"type"`
+	ActorID       string    `json:"actor_id,omitempty"`
+	OldReviewerID string    `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type PRHistory struct {
+	PullRequestID string    `json:"pull_request_id"`
+	Events        []PREvent `json:"events"`
+}
+
 type UserReviews struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
 }
+
+type CandidateTrace struct {
+	UserID         string `json:"user_id"`
+	TeamName       string `json:"team_name"`
+	OpenReviews    int    `json:"open_reviews"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+	Status         string `json:"status"`
+	Details        string `json:"details,omitempty"`
+}
+
+type AssignmentPreview struct {
+	AuthorID          string            `json:"author_id"`
+	TeamName          string            `json:"team_name"`
+	AssignedReviewers []string          `json:"assigned_reviewers"`
+	ReviewerTeams     map[string]string `json:"reviewer_teams,omitempty"`
+	UnmatchedLabels   []string          `json:"unmatched_labels,omitempty"`
+	Candidates        []CandidateTrace  `json:"candidates"`
+	Error             string            `json:"error,omitempty"`
+}
@@ -0,0 +1,25 @@
+package resp
+
+type ReviewerRule struct {
+	Type       string `json:"type"`
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+}
+
+type ReviewerRules struct {
+	TeamName string         `json:"team_name"`
+	Rules    []ReviewerRule `json:"rules"`
+}
+
+type RuleCheck struct {
+	UserID   string        `json:"user_id"`
+	TeamName string        `json:"team_name"`
+	Decision string        `json:"decision"`
+	Rule     *ReviewerRule `json:"rule,omitempty"`
+}
+
+type RulesDryRun struct {
+	AuthorID   string      `json:"author_id"`
+	TeamName   string      `json:"team_name"`
+	Candidates []RuleCheck `json:"candidates"`
+}
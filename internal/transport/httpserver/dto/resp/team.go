@@ -1,16 +1,48 @@
 package resp
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string   `json:"user_id"`
+	Username       string   `json:"username"`
+	IsActive       bool     `json:"is_active"`
+	MaxOpenReviews int      `json:"max_open_reviews,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Seniority      string   `json:"seniority,omitempty"`
+}
+
+type RoleQuota struct {
+	Seniority string `json:"seniority"`
+	Count     int    `json:"count"`
 }
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName       string       `json:"team_name"`
+	Members        []TeamMember `json:"members"`
+	MinReviewers   int          `json:"min_reviewers"`
+	MaxReviewers   int          `json:"max_reviewers"`
+	MaxOpenReviews int          `json:"max_open_reviews,omitempty"`
+	FallbackTeams  []string     `json:"fallback_teams,omitempty"`
+	RoleQuotas     []RoleQuota  `json:"role_quotas,omitempty"`
 }
 
 type TeamAdd struct {
 	Team Team `json:"team"`
 }
+
+type TeamRemoveMember struct {
+	Team Team `json:"team"`
+	ReassignmentReport
+}
+
+type TeamDeactivateUsers struct {
+	Team Team `json:"team"`
+	ReassignmentReport
+}
+
+type TeamDelete struct {
+	TeamName string `json:"team_name"`
+}
+
+type TeamAbsences struct {
+	TeamName string    `json:"team_name"`
+	Absences []Absence `json:"absences"`
+}
@@ -1,12 +1,48 @@
 package resp
 
+import "time"
+
 type User struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID         string   `json:"user_id"`
+	Username       string   `json:"username"`
+	TeamName       string   `json:"team_name"`
+	IsActive       bool     `json:"is_active"`
+	MaxOpenReviews int      `json:"max_open_reviews,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Seniority      string   `json:"seniority,omitempty"`
 }
 
+// SetIsActive содержит отчёт о переназначении только для запросов с reassign.
 type SetIsActive struct {
 	User User `json:"user"`
+	*ReassignmentReport
+}
+
+type MoveTeam struct {
+	User User `json:"user"`
+	ReassignmentReport
+}
+
+type Absence struct {
+	AbsenceID int64     `json:"absence_id"`
+	UserID    string    `json:"user_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+}
+
+type AddAbsence struct {
+	Absence Absence `json:"absence"`
+}
+
+type SetMaxOpenReviews struct {
+	User User `json:"user"`
+}
+
+type SetTags struct {
+	User User `json:"user"`
+}
+
+type SetSeniority struct {
+	User User `json:"user"`
 }
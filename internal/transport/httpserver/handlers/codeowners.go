@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/req"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/utils"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/utils/mappers"
+)
+
+type CodeOwnersHandler struct {
+	svc *review.Service
+	log *slog.Logger
+}
+
+func NewCodeOwnersHandler(svc *review.Service, l *slog.Logger) *CodeOwnersHandler {
+	return &CodeOwnersHandler{svc: svc, log: l}
+}
+
+func (h *CodeOwnersHandler) SetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var body req.SetCodeOwners
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetCodeOwners", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.Repository == "" {
+		h.log.Warn("missing repository in SetCodeOwners")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "repository is required")
+		return
+	}
+
+	h.log.Info("SetCodeOwners called", "repository", body.Repository, "rules_count", len(body.Rules))
+	rules, err := h.svc.SetOwnershipRules(r.Context(), body.Repository, mappers.FromOwnershipRulesReq(body.Rules))
+	if err != nil {
+		h.log.Error("failed to set code owners", "repository", body.Repository, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("code owners updated", "repository", body.Repository, "rules_count", len(rules))
+	utils.RespondJSON(w, http.StatusOK, mappers.ToDTOCodeOwners(body.Repository, rules))
+}
+
+func (h *CodeOwnersHandler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	repository := r.URL.Query().Get("repository")
+	if repository == "" {
+		h.log.Warn("missing repository in GetCodeOwners")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "repository is required")
+		return
+	}
+
+	rules, err := h.svc.ListOwnershipRules(r.Context(), repository)
+	if err != nil {
+		h.log.Error("failed to list code owners", "repository", repository, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("code owners retrieved", "repository", repository, "rules_count", len(rules))
+	utils.RespondJSON(w, http.StatusOK, mappers.ToDTOCodeOwners(repository, rules))
+}
@@ -33,11 +33,16 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id, pull_request_name and author_id are required")
 		return
 	}
+	if body.ReviewersCount != nil && *body.ReviewersCount < 1 {
+		h.log.Warn("invalid reviewers_count in CreatePR", "reviewers_count", *body.ReviewersCount)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "reviewers_count must be positive")
+		return
+	}
 
 	h.log.Info("CreatePR called", "pr_id", body.PullRequestID, "author_id", body.AuthorID, "pr_title", body.PullRequestName)
 
-	pr := mappers.FromCreatePRReq(body.PullRequestID, body.AuthorID, body.PullRequestName)
-	created, err := h.svc.CreatePR(r.Context(), pr)
+	pr := mappers.FromCreatePRReq(body)
+	created, err := h.svc.CreatePR(r.Context(), pr, mappers.CreatePROptionsFromReq(body))
 	if err != nil {
 		h.log.Error("failed to create PR", "pr_id", body.PullRequestID, "error", err)
 		if utils.HandleDomainError(w, err) {
@@ -51,6 +56,84 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusCreated, resp.CreatePR{PR: mappers.ToDTOPR(created)})
 }
 
+// PreviewAssignment показывает, кого CreatePR назначил бы ревьюверами, и
+// почему остальные кандидаты не подошли; PR не создаётся.
+func (h *PRHandler) PreviewAssignment(w http.ResponseWriter, r *http.Request) {
+	var body req.PreviewAssignment
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in PreviewAssignment", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.AuthorID == "" {
+		h.log.Warn("missing author_id in PreviewAssignment")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "author_id is required")
+		return
+	}
+	if body.ReviewersCount != nil && *body.ReviewersCount < 1 {
+		h.log.Warn("invalid reviewers_count in PreviewAssignment", "reviewers_count", *body.ReviewersCount)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "reviewers_count must be positive")
+		return
+	}
+
+	pr, opts := mappers.FromPreviewAssignmentReq(body)
+	preview, err := h.svc.PreviewAssignment(r.Context(), pr, opts)
+	if err != nil {
+		h.log.Error("failed to preview assignment", "author_id", body.AuthorID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, mappers.ToDTOAssignmentPreview(preview))
+}
+
+func (h *PRHandler) ReadyPR(w http.ResponseWriter, r *http.Request) {
+	var body req.ReadyPR
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in ReadyPR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.PullRequestID == "" {
+		h.log.Warn("missing pull_request_id in ReadyPR")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+	reviewersCount := 0
+	if body.ReviewersCount != nil {
+		if *body.ReviewersCount < 1 {
+			h.log.Warn("invalid reviewers_count in ReadyPR", "reviewers_count", *body.ReviewersCount)
+			utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "reviewers_count must be positive")
+			return
+		}
+		reviewersCount = *body.ReviewersCount
+	}
+
+	ctx, err := utils.WithExpectedVersion(r, body.ExpectedVersion)
+	if err != nil {
+		h.log.Warn("invalid expected version in ReadyPR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	h.log.Info("ReadyPR called", "pr_id", body.PullRequestID)
+	ready, err := h.svc.ReadyPR(ctx, body.PullRequestID, reviewersCount)
+	if err != nil {
+		h.log.Error("failed to mark PR as ready", "pr_id", body.PullRequestID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("PR ready for review", "pr_id", ready.ID)
+	utils.RespondJSON(w, http.StatusOK, resp.ReadyPR{PR: mappers.ToDTOPR(ready)})
+}
+
 func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	var body req.MergePR
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -64,8 +147,15 @@ func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.log.Info("MergePR called", "pr_id", body.PullRequestID)
-	merged, err := h.svc.MergePR(r.Context(), body.PullRequestID)
+	ctx, err := utils.WithExpectedVersion(r, body.ExpectedVersion)
+	if err != nil {
+		h.log.Warn("invalid expected version in MergePR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	h.log.Info("MergePR called", "pr_id", body.PullRequestID, "force", body.Force)
+	merged, err := h.svc.MergePR(ctx, body.PullRequestID, body.Force)
 	if err != nil {
 		h.log.Error("failed to merge PR", "pr_id", body.PullRequestID, "error", err)
 		if utils.HandleDomainError(w, err) {
@@ -79,6 +169,76 @@ func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, resp.MergePR{PR: mappers.ToDTOPR(merged)})
 }
 
+func (h *PRHandler) ClosePR(w http.ResponseWriter, r *http.Request) {
+	var body req.ClosePR
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in ClosePR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.PullRequestID == "" {
+		h.log.Warn("missing pull_request_id in ClosePR")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx, err := utils.WithExpectedVersion(r, body.ExpectedVersion)
+	if err != nil {
+		h.log.Warn("invalid expected version in ClosePR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	h.log.Info("ClosePR called", "pr_id", body.PullRequestID)
+	closed, err := h.svc.ClosePR(ctx, body.PullRequestID)
+	if err != nil {
+		h.log.Error("failed to close PR", "pr_id", body.PullRequestID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("PR closed successfully", "pr_id", closed.ID)
+	utils.RespondJSON(w, http.StatusOK, resp.ClosePR{PR: mappers.ToDTOPR(closed)})
+}
+
+func (h *PRHandler) ReopenPR(w http.ResponseWriter, r *http.Request) {
+	var body req.ReopenPR
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in ReopenPR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.PullRequestID == "" {
+		h.log.Warn("missing pull_request_id in ReopenPR")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx, err := utils.WithExpectedVersion(r, body.ExpectedVersion)
+	if err != nil {
+		h.log.Warn("invalid expected version in ReopenPR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	h.log.Info("ReopenPR called", "pr_id", body.PullRequestID)
+	reopened, err := h.svc.ReopenPR(ctx, body.PullRequestID)
+	if err != nil {
+		h.log.Error("failed to reopen PR", "pr_id", body.PullRequestID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("PR reopened successfully", "pr_id", reopened.ID)
+	utils.RespondJSON(w, http.StatusOK, resp.ReopenPR{PR: mappers.ToDTOPR(reopened)})
+}
+
 func (h *PRHandler) ReassignPR(w http.ResponseWriter, r *http.Request) {
 	var body req.ReassignReviewer
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -92,8 +252,15 @@ func (h *PRHandler) ReassignPR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, err := utils.WithExpectedVersion(r, body.ExpectedVersion)
+	if err != nil {
+		h.log.Warn("invalid expected version in ReassignPR", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
 	h.log.Info("ReassignPR called", "pr_id", body.PullRequestID, "old_reviewer_id", body.OldUserID)
-	pr, replacedBy, err := h.svc.ReassignReviewer(r.Context(), body.PullRequestID, body.OldUserID)
+	pr, replacedBy, err := h.svc.ReassignReviewer(ctx, body.PullRequestID, body.OldUserID)
 	if err != nil {
 		h.log.Error("failed to reassign PR", "pr_id", body.PullRequestID, "error", err)
 		if utils.HandleDomainError(w, err) {
@@ -109,3 +276,66 @@ func (h *PRHandler) ReassignPR(w http.ResponseWriter, r *http.Request) {
 		ReplacedBy: replacedBy,
 	})
 }
+
+func (h *PRHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var body req.SubmitReview
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SubmitReview", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.PullRequestID == "" || body.ReviewerID == "" || body.State == "" {
+		h.log.Warn("missing required fields in SubmitReview", "body", body)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id, reviewer_id and state are required")
+		return
+	}
+
+	ctx, err := utils.WithExpectedVersion(r, body.ExpectedVersion)
+	if err != nil {
+		h.log.Warn("invalid expected version in SubmitReview", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	h.log.Info("SubmitReview called", "pr_id", body.PullRequestID, "reviewer_id", body.ReviewerID, "state", body.State)
+	pr, err := h.svc.SubmitReview(ctx, body.PullRequestID, body.ReviewerID, review.ReviewState(body.State), body.Body)
+	if err != nil {
+		h.log.Error("failed to submit review", "pr_id", body.PullRequestID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("review submitted successfully", "pr_id", pr.ID, "reviewer_id", body.ReviewerID)
+	utils.RespondJSON(w, http.StatusOK, resp.SubmitReview{PR: mappers.ToDTOPR(pr)})
+}
+
+func (h *PRHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		h.log.Warn("missing pull_request_id in GetHistory")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	h.log.Info("GetHistory called", "pr_id", prID)
+	events, err := h.svc.GetPRHistory(r.Context(), prID)
+	if err != nil {
+		h.log.Error("failed to get PR history", "pr_id", prID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	out := make([]resp.PREvent, 0, len(events))
+	for _, ev := range events {
+		out = append(out, mappers.ToDTOEvent(ev))
+	}
+
+	h.log.Info("PR history retrieved", "pr_id", prID, "count", len(out))
+	utils.RespondJSON(w, http.StatusOK, resp.PRHistory{PullRequestID: prID, Events: out})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/req"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/utils"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/utils/mappers"
+)
+
+type ReviewerRulesHandler struct {
+	svc *review.Service
+	log *slog.Logger
+}
+
+func NewReviewerRulesHandler(svc *review.Service, l *slog.Logger) *ReviewerRulesHandler {
+	return &ReviewerRulesHandler{svc: svc, log: l}
+}
+
+func (h *ReviewerRulesHandler) SetRules(w http.ResponseWriter, r *http.Request) {
+	var body req.SetReviewerRules
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetReviewerRules", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" {
+		h.log.Warn("missing team_name in SetReviewerRules")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	h.log.Info("SetReviewerRules called", "team_name", body.TeamName, "rules_count", len(body.Rules))
+	rules, err := h.svc.SetReviewerRules(r.Context(), body.TeamName, mappers.FromReviewerRulesReq(body.Rules))
+	if err != nil {
+		h.log.Error("failed to set reviewer rules", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("reviewer rules updated", "team_name", body.TeamName, "rules_count", len(rules))
+	utils.RespondJSON(w, http.StatusOK, mappers.ToDTOReviewerRules(body.TeamName, rules))
+}
+
+func (h *ReviewerRulesHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.log.Warn("missing team_name in GetReviewerRules")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	rules, err := h.svc.ListReviewerRules(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to list reviewer rules", "team_name", teamName, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("reviewer rules retrieved", "team_name", teamName, "rules_count", len(rules))
+	utils.RespondJSON(w, http.StatusOK, mappers.ToDTOReviewerRules(teamName, rules))
+}
+
+func (h *ReviewerRulesHandler) DryRun(w http.ResponseWriter, r *http.Request) {
+	authorID := r.URL.Query().Get("author_id")
+	if authorID == "" {
+		h.log.Warn("missing author_id in DryRunReviewerRules")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "author_id is required")
+		return
+	}
+
+	res, err := h.svc.DryRunReviewerRules(r.Context(), authorID)
+	if err != nil {
+		h.log.Error("failed to dry-run reviewer rules", "author_id", authorID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("reviewer rules dry-run completed", "author_id", authorID, "candidates", len(res.Candidates))
+	utils.RespondJSON(w, http.StatusOK, mappers.ToDTORulesDryRun(res))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/zapevnik/pr-review-service/internal/domain/review"
 	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/req"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/resp"
 	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/utils"
 	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/utils/mappers"
 )
@@ -30,13 +31,14 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	teamName, members, err := mappers.TeamAddRequestToArgs(body)
+	team, members, err := mappers.TeamAddRequestToArgs(body)
 	if err != nil {
 		h.log.Warn("failed to parse TeamAdd request", "error", err)
 		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
 		return
 	}
 
+	teamName := team.Name
 	h.log.Info("CreateTeam called", "team_name", teamName, "members_count", len(members))
 
 	_, err = h.svc.GetByName(ctx, teamName)
@@ -51,20 +53,27 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdTeam, err := h.svc.CreateTeam(ctx, teamName, members)
-	if err != nil {
+	if _, err := h.svc.CreateTeam(ctx, team, members); err != nil {
 		if errors.Is(err, review.ErrUserInAnotherTeam) {
 			h.log.Warn("user already in another team", "team_name", teamName)
 			utils.WriteError(w, http.StatusConflict, "USER_IN_ANOTHER_TEAM", "user is already in another team")
 			return
 		}
 		h.log.Error("failed to create team", "team_name", teamName, "error", err)
-		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
 		return
 	}
 
+	// Ответ собирается из сохранённых данных: у уже существовавших
+	// пользователей сохраняются лимит, уровень и теги, которых нет в запросе.
+	respTeam, ok := h.teamResponse(w, r, teamName)
+	if !ok {
+		return
+	}
 	h.log.Info("team created successfully", "team_name", teamName)
-	respTeam := mappers.TeamToResponse(createdTeam, members)
 	utils.RespondJSON(w, http.StatusCreated, map[string]any{"team": respTeam})
 }
 
@@ -91,7 +100,385 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fallbacks, err := h.svc.ListTeamFallbacks(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to list fallback teams", "team_name", teamName, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	quotas, err := h.svc.ListTeamRoleQuotas(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to list role quotas", "team_name", teamName, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
 	h.log.Info("team retrieved successfully", "team_name", teamName, "members_count", len(members))
 	respTeam := mappers.TeamToResponse(team, members)
+	respTeam.FallbackTeams = fallbacks
+	respTeam.RoleQuotas = mappers.ToDTORoleQuotas(quotas)
 	utils.RespondJSON(w, http.StatusOK, map[string]any{"team": respTeam})
 }
+
+func (h *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamAddMember
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in AddMember", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" || body.UserID == "" {
+		h.log.Warn("missing required fields in AddMember", "body", body)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_id are required")
+		return
+	}
+
+	h.log.Info("AddMember called", "team_name", body.TeamName, "user_id", body.UserID)
+	_, err := h.svc.AddTeamMember(r.Context(), body.TeamName, review.User{
+		ID:             body.UserID,
+		Name:           body.Username,
+		IsActive:       body.IsActive,
+		MaxOpenReviews: body.MaxOpenReviews,
+		Seniority:      review.Seniority(body.Seniority),
+	})
+	if err != nil {
+		h.log.Error("failed to add team member", "team_name", body.TeamName, "user_id", body.UserID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.TeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team member added successfully", "team_name", body.TeamName, "user_id", body.UserID)
+	utils.RespondJSON(w, http.StatusOK, resp.TeamAdd{Team: team})
+}
+
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamRemoveMember
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in RemoveMember", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" || body.UserID == "" {
+		h.log.Warn("missing required fields in RemoveMember", "body", body)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_id are required")
+		return
+	}
+
+	h.log.Info("RemoveMember called", "team_name", body.TeamName, "user_id", body.UserID)
+	report, err := h.svc.RemoveTeamMember(r.Context(), body.TeamName, body.UserID)
+	if err != nil {
+		h.log.Error("failed to remove team member", "team_name", body.TeamName, "user_id", body.UserID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.TeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team member removed successfully", "team_name", body.TeamName, "user_id", body.UserID,
+		"reassigned", len(report.Reassigned), "failed", len(report.Failed))
+	utils.RespondJSON(w, http.StatusOK, resp.TeamRemoveMember{
+		Team:               team,
+		ReassignmentReport: mappers.ToDTOReassignmentReport(report),
+	})
+}
+
+func (h *TeamHandler) RenameTeam(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamRename
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in RenameTeam", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" || body.NewTeamName == "" {
+		h.log.Warn("missing required fields in RenameTeam", "body", body)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and new_team_name are required")
+		return
+	}
+
+	h.log.Info("RenameTeam called", "team_name", body.TeamName, "new_team_name", body.NewTeamName)
+	if _, err := h.svc.RenameTeam(r.Context(), body.TeamName, body.NewTeamName); err != nil {
+		h.log.Error("failed to rename team", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.NewTeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team renamed successfully", "team_name", body.TeamName, "new_team_name", body.NewTeamName)
+	utils.RespondJSON(w, http.StatusOK, resp.TeamAdd{Team: team})
+}
+
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamDelete
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in DeleteTeam", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" {
+		h.log.Warn("missing team_name in DeleteTeam")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	h.log.Info("DeleteTeam called", "team_name", body.TeamName)
+	if err := h.svc.DeleteTeam(r.Context(), body.TeamName); err != nil {
+		h.log.Error("failed to delete team", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("team deleted successfully", "team_name", body.TeamName)
+	utils.RespondJSON(w, http.StatusOK, resp.TeamDelete{TeamName: body.TeamName})
+}
+
+func (h *TeamHandler) DeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamDeactivateUsers
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in DeactivateUsers", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" || len(body.UserIDs) == 0 {
+		h.log.Warn("missing required fields in DeactivateUsers", "body", body)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_ids are required")
+		return
+	}
+
+	h.log.Info("DeactivateUsers called", "team_name", body.TeamName, "users_count", len(body.UserIDs))
+	report, err := h.svc.DeactivateTeamUsers(r.Context(), body.TeamName, body.UserIDs)
+	if err != nil {
+		h.log.Error("failed to deactivate team users", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.TeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team users deactivated successfully", "team_name", body.TeamName,
+		"reassigned", len(report.Reassigned), "failed", len(report.Failed))
+	utils.RespondJSON(w, http.StatusOK, resp.TeamDeactivateUsers{
+		Team:               team,
+		ReassignmentReport: mappers.ToDTOReassignmentReport(report),
+	})
+}
+
+func (h *TeamHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamSetMaxOpenReviews
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetMaxOpenReviews", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" {
+		h.log.Warn("missing team_name in SetMaxOpenReviews")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	h.log.Info("SetMaxOpenReviews called", "team_name", body.TeamName, "max_open_reviews", body.MaxOpenReviews)
+	if _, err := h.svc.SetTeamMaxOpenReviews(r.Context(), body.TeamName, body.MaxOpenReviews); err != nil {
+		h.log.Error("failed to set team max open reviews", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.TeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team max open reviews updated", "team_name", body.TeamName, "max_open_reviews", body.MaxOpenReviews)
+	utils.RespondJSON(w, http.StatusOK, resp.TeamAdd{Team: team})
+}
+
+func (h *TeamHandler) SetReviewersLimits(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamSetReviewersLimits
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetReviewersLimits", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" {
+		h.log.Warn("missing team_name in SetReviewersLimits")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+	if body.MinReviewers == nil || body.MaxReviewers == nil {
+		h.log.Warn("missing reviewers limits in SetReviewersLimits", "team_name", body.TeamName)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "min_reviewers and max_reviewers are required")
+		return
+	}
+
+	h.log.Info("SetReviewersLimits called", "team_name", body.TeamName, "min", *body.MinReviewers, "max", *body.MaxReviewers)
+	if _, err := h.svc.SetTeamReviewersLimits(r.Context(), body.TeamName, *body.MinReviewers, *body.MaxReviewers); err != nil {
+		h.log.Error("failed to set team reviewers limits", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.TeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team reviewers limits updated", "team_name", body.TeamName)
+	utils.RespondJSON(w, http.StatusOK, resp.TeamAdd{Team: team})
+}
+
+func (h *TeamHandler) SetFallbacks(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamSetFallbacks
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetFallbacks", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" {
+		h.log.Warn("missing team_name in SetFallbacks")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	h.log.Info("SetFallbacks called", "team_name", body.TeamName, "fallback_teams", body.FallbackTeams)
+	if _, err := h.svc.SetTeamFallbacks(r.Context(), body.TeamName, body.FallbackTeams); err != nil {
+		h.log.Error("failed to set fallback teams", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.TeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team fallbacks updated", "team_name", body.TeamName, "fallback_teams", team.FallbackTeams)
+	utils.RespondJSON(w, http.StatusOK, resp.TeamAdd{Team: team})
+}
+
+func (h *TeamHandler) SetRoleQuotas(w http.ResponseWriter, r *http.Request) {
+	var body req.TeamSetRoleQuotas
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetRoleQuotas", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.TeamName == "" {
+		h.log.Warn("missing team_name in SetRoleQuotas")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	h.log.Info("SetRoleQuotas called", "team_name", body.TeamName, "role_quotas", body.RoleQuotas)
+	if _, err := h.svc.SetTeamRoleQuotas(r.Context(), body.TeamName, mappers.FromRoleQuotasReq(body.RoleQuotas)); err != nil {
+		h.log.Error("failed to set role quotas", "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	team, ok := h.teamResponse(w, r, body.TeamName)
+	if !ok {
+		return
+	}
+	h.log.Info("team role quotas updated", "team_name", body.TeamName, "role_quotas", team.RoleQuotas)
+	utils.RespondJSON(w, http.StatusOK, resp.TeamAdd{Team: team})
+}
+
+// teamResponse загружает команду с участниками для ответа; при ошибке
+// сам пишет ответ и возвращает false.
+func (h *TeamHandler) teamResponse(w http.ResponseWriter, r *http.Request, teamName string) (resp.Team, bool) {
+	team, err := h.svc.GetByName(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to get team", "team_name", teamName, "error", err)
+		if !utils.HandleDomainError(w, err) {
+			utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		}
+		return resp.Team{}, false
+	}
+
+	members, err := h.svc.ListByTeam(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to list team members", "team_name", teamName, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return resp.Team{}, false
+	}
+
+	fallbacks, err := h.svc.ListTeamFallbacks(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to list fallback teams", "team_name", teamName, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return resp.Team{}, false
+	}
+
+	quotas, err := h.svc.ListTeamRoleQuotas(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to list role quotas", "team_name", teamName, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return resp.Team{}, false
+	}
+
+	respTeam := mappers.TeamToResponse(team, members)
+	respTeam.FallbackTeams = fallbacks
+	respTeam.RoleQuotas = mappers.ToDTORoleQuotas(quotas)
+	return respTeam, true
+}
+
+func (h *TeamHandler) GetAbsences(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.log.Warn("missing team_name in GetAbsences")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	h.log.Info("GetAbsences called", "team_name", teamName)
+	absences, err := h.svc.ListTeamAbsences(r.Context(), teamName)
+	if err != nil {
+		h.log.Error("failed to list team absences", "team_name", teamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("team absences retrieved successfully", "team_name", teamName, "absences_count", len(absences))
+	utils.RespondJSON(w, http.StatusOK, resp.TeamAbsences{
+		TeamName: teamName,
+		Absences: mappers.ToDTOAbsences(absences),
+	})
+}
@@ -37,30 +37,31 @@ func (h *UserHandler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.log.Info("SetUserActive called", "user_id", req.UserID, "is_active", req.IsActive)
+	h.log.Info("SetUserActive called", "user_id", req.UserID, "is_active", req.IsActive, "reassign", req.Reassign)
 
-	user, err := h.svc.GetUserByID(ctx, req.UserID)
+	updated, report, err := h.svc.SetUserActive(ctx, req.UserID, req.IsActive, req.Reassign)
 	if err != nil {
 		if errors.Is(err, review.ErrNotFound) {
 			h.log.Warn("user not found", "user_id", req.UserID)
 			utils.WriteError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
 		} else {
-			h.log.Error("failed to get user", "user_id", req.UserID, "error", err)
-			utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+			h.log.Error("failed to update user active status", "user_id", req.UserID, "error", err)
+			if !utils.HandleDomainError(w, err) {
+				utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+			}
 		}
 		return
 	}
 
-	user.IsActive = req.IsActive
-	updated, err := h.svc.UpdateUser(ctx, user)
-	if err != nil {
-		h.log.Error("failed to update user active status", "user_id", req.UserID, "error", err)
-		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
-		return
+	out := resp.SetIsActive{User: mappers.ToDTOUser(updated)}
+	if req.Reassign && !req.IsActive {
+		dto := mappers.ToDTOReassignmentReport(report)
+		out.ReassignmentReport = &dto
 	}
 
-	h.log.Info("user active status updated", "user_id", updated.ID, "is_active", updated.IsActive)
-	utils.RespondJSON(w, http.StatusOK, map[string]any{"user": mappers.ToDTOUser(updated)})
+	h.log.Info("user active status updated", "user_id", updated.ID, "is_active", updated.IsActive,
+		"reassigned", len(report.Reassigned), "failed", len(report.Failed))
+	utils.RespondJSON(w, http.StatusOK, out)
 }
 
 func (h *UserHandler) GetAssignedPRs(w http.ResponseWriter, r *http.Request) {
@@ -91,3 +92,147 @@ func (h *UserHandler) GetAssignedPRs(w http.ResponseWriter, r *http.Request) {
 		"pull_requests": out,
 	})
 }
+
+func (h *UserHandler) MoveTeam(w http.ResponseWriter, r *http.Request) {
+	var body req.MoveTeam
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in MoveTeam", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.UserID == "" || body.TeamName == "" {
+		h.log.Warn("missing required fields in MoveTeam", "body", body)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id and team_name are required")
+		return
+	}
+
+	h.log.Info("MoveTeam called", "user_id", body.UserID, "team_name", body.TeamName)
+	user, report, err := h.svc.MoveUserToTeam(r.Context(), body.UserID, body.TeamName)
+	if err != nil {
+		h.log.Error("failed to move user", "user_id", body.UserID, "team_name", body.TeamName, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("user moved successfully", "user_id", user.ID, "team_name", user.Team,
+		"reassigned", len(report.Reassigned), "failed", len(report.Failed))
+	utils.RespondJSON(w, http.StatusOK, resp.MoveTeam{
+		User:               mappers.ToDTOUser(user),
+		ReassignmentReport: mappers.ToDTOReassignmentReport(report),
+	})
+}
+
+func (h *UserHandler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	var body req.AddAbsence
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in AddAbsence", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.UserID == "" {
+		h.log.Warn("missing user_id in AddAbsence")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	h.log.Info("AddAbsence called", "user_id", body.UserID, "starts_at", body.StartsAt, "ends_at", body.EndsAt)
+	created, err := h.svc.AddAbsence(r.Context(), mappers.FromAddAbsenceReq(body))
+	if err != nil {
+		h.log.Error("failed to add absence", "user_id", body.UserID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("absence added successfully", "user_id", created.UserID, "absence_id", created.ID)
+	utils.RespondJSON(w, http.StatusCreated, resp.AddAbsence{Absence: mappers.ToDTOAbsence(created)})
+}
+
+func (h *UserHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var body req.SetMaxOpenReviews
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetMaxOpenReviews", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.UserID == "" {
+		h.log.Warn("missing user_id in SetMaxOpenReviews")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	h.log.Info("SetMaxOpenReviews called", "user_id", body.UserID, "max_open_reviews", body.MaxOpenReviews)
+	user, err := h.svc.SetUserMaxOpenReviews(r.Context(), body.UserID, body.MaxOpenReviews)
+	if err != nil {
+		h.log.Error("failed to set user max open reviews", "user_id", body.UserID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("user max open reviews updated", "user_id", user.ID, "max_open_reviews", user.MaxOpenReviews)
+	utils.RespondJSON(w, http.StatusOK, resp.SetMaxOpenReviews{User: mappers.ToDTOUser(user)})
+}
+
+func (h *UserHandler) SetTags(w http.ResponseWriter, r *http.Request) {
+	var body req.SetTags
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetTags", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.UserID == "" {
+		h.log.Warn("missing user_id in SetTags")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	h.log.Info("SetTags called", "user_id", body.UserID, "tags", body.Tags)
+	user, err := h.svc.SetUserTags(r.Context(), body.UserID, body.Tags)
+	if err != nil {
+		h.log.Error("failed to set user tags", "user_id", body.UserID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("user tags updated", "user_id", user.ID, "tags", user.Tags)
+	utils.RespondJSON(w, http.StatusOK, resp.SetTags{User: mappers.ToDTOUser(user)})
+}
+
+func (h *UserHandler) SetSeniority(w http.ResponseWriter, r *http.Request) {
+	var body req.SetSeniority
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.log.Error("invalid JSON in SetSeniority", "error", err)
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+	if body.UserID == "" {
+		h.log.Warn("missing user_id in SetSeniority")
+		utils.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	h.log.Info("SetSeniority called", "user_id", body.UserID, "seniority", body.Seniority)
+	user, err := h.svc.SetUserSeniority(r.Context(), body.UserID, review.Seniority(body.Seniority))
+	if err != nil {
+		h.log.Error("failed to set user seniority", "user_id", body.UserID, "error", err)
+		if utils.HandleDomainError(w, err) {
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	h.log.Info("user seniority updated", "user_id", user.ID, "seniority", user.Seniority)
+	utils.RespondJSON(w, http.StatusOK, resp.SetSeniority{User: mappers.ToDTOUser(user)})
+}
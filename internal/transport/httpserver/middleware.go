@@ -1,10 +1,16 @@
 package httpserver
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
 )
 
+// ActorHeader — заголовок с user_id инициатора запроса, попадает в историю PR.
+const ActorHeader = "X-Actor-ID"
+
 func UseMiddlewares(r chi.Router) {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
@@ -13,4 +19,14 @@ func UseMiddlewares(r chi.Router) {
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
+	r.Use(actorMiddleware)
+}
+
+func actorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get(ActorHeader); actor != "" {
+			r = r.WithContext(review.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
 }
@@ -11,6 +11,8 @@ func NewRouter(
 	team *handlers.TeamHandler,
 	user *handlers.UserHandler,
 	pr *handlers.PRHandler,
+	codeOwners *handlers.CodeOwnersHandler,
+	reviewerRules *handlers.ReviewerRulesHandler,
 ) http.Handler {
 	r := chi.NewRouter()
 	UseMiddlewares(r)
@@ -18,6 +20,8 @@ func NewRouter(
 	registerTeamRoutes(r, team)
 	registerUserRoutes(r, user)
 	registerPRRoutes(r, pr)
+	registerCodeOwnersRoutes(r, codeOwners)
+	registerReviewerRulesRoutes(r, reviewerRules)
 
 	return r
 }
@@ -26,6 +30,16 @@ func registerTeamRoutes(r chi.Router, h *handlers.TeamHandler) {
 	r.Route("/team", func(r chi.Router) {
 		r.Post("/add", h.CreateTeam)
 		r.Get("/get", h.GetTeam)
+		r.Post("/addMember", h.AddMember)
+		r.Post("/removeMember", h.RemoveMember)
+		r.Post("/rename", h.RenameTeam)
+		r.Post("/delete", h.DeleteTeam)
+		r.Post("/deactivateUsers", h.DeactivateUsers)
+		r.Get("/absences", h.GetAbsences)
+		r.Post("/setMaxOpenReviews", h.SetMaxOpenReviews)
+		r.Post("/setReviewersLimits", h.SetReviewersLimits)
+		r.Post("/setFallbacks", h.SetFallbacks)
+		r.Post("/setRoleQuotas", h.SetRoleQuotas)
 	})
 }
 
@@ -33,13 +47,39 @@ func registerUserRoutes(r chi.Router, h *handlers.UserHandler) {
 	r.Route("/users", func(r chi.Router) {
 		r.Post("/setIsActive", h.SetUserActive)
 		r.Get("/getReview", h.GetAssignedPRs)
+		r.Post("/moveTeam", h.MoveTeam)
+		r.Post("/addAbsence", h.AddAbsence)
+		r.Post("/setMaxOpenReviews", h.SetMaxOpenReviews)
+		r.Post("/setTags", h.SetTags)
+		r.Post("/setSeniority", h.SetSeniority)
 	})
 }
 
 func registerPRRoutes(r chi.Router, h *handlers.PRHandler) {
 	r.Route("/pullRequest", func(r chi.Router) {
 		r.Post("/create", h.CreatePR)
+		r.Post("/previewAssignment", h.PreviewAssignment)
+		r.Post("/ready", h.ReadyPR)
 		r.Post("/merge", h.MergePR)
+		r.Post("/close", h.ClosePR)
+		r.Post("/reopen", h.ReopenPR)
 		r.Post("/reassign", h.ReassignPR)
+		r.Post("/review", h.SubmitReview)
+		r.Get("/history", h.GetHistory)
+	})
+}
+
+func registerCodeOwnersRoutes(r chi.Router, h *handlers.CodeOwnersHandler) {
+	r.Route("/codeOwners", func(r chi.Router) {
+		r.Post("/set", h.SetCodeOwners)
+		r.Get("/get", h.GetCodeOwners)
+	})
+}
+
+func registerReviewerRulesRoutes(r chi.Router, h *handlers.ReviewerRulesHandler) {
+	r.Route("/reviewerRules", func(r chi.Router) {
+		r.Post("/set", h.SetRules)
+		r.Get("/get", h.GetRules)
+		r.Get("/dryRun", h.DryRun)
 	})
 }
@@ -0,0 +1,30 @@
+package mappers
+
+import (
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/req"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/resp"
+)
+
+// FromOwnershipRulesReq маппит []req.OwnershipRule -> []review.OwnershipRule
+func FromOwnershipRulesReq(rules []req.OwnershipRule) []review.OwnershipRule {
+	out := make([]review.OwnershipRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, review.OwnershipRule{Pattern: r.Pattern, Owners: r.Owners})
+	}
+	return out
+}
+
+// ToDTOCodeOwners маппит правила владения репозитория -> resp.CodeOwners
+func ToDTOCodeOwners(repository string, rules []review.OwnershipRule) resp.CodeOwners {
+	out := resp.CodeOwners{
+		Repository: repository,
+		Rules:      make([]resp.OwnershipRule, 0, len(rules)),
+	}
+	for _, r := range rules {
+		owners := make([]string, 0, len(r.Owners))
+		owners = append(owners, r.Owners...)
+		out.Rules = append(out.Rules, resp.OwnershipRule{Pattern: r.Pattern, Owners: owners})
+	}
+	return out
+}
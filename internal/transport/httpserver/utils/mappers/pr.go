@@ -2,6 +2,7 @@ package mappers
 
 import (
 	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/req"
 	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/resp"
 )
 
@@ -10,14 +11,34 @@ func ToDTOPR(pr review.PullRequest) resp.PullRequest {
 	reviewers := make([]string, 0, len(pr.ReviewerIDs))
 	reviewers = append(reviewers, pr.ReviewerIDs...)
 
+	domainStates := pr.ReviewerStates()
+	states := make([]resp.ReviewerState, 0, len(domainStates))
+	for _, st := range domainStates {
+		states = append(states, resp.ReviewerState{
+			UserID:      st.ReviewerID,
+			State:       string(st.State),
+			Body:        st.Body,
+			SubmittedAt: st.SubmittedAt,
+		})
+	}
+
 	return resp.PullRequest{
 		PullRequestID:     pr.ID,
 		PullRequestName:   pr.Title,
 		AuthorID:          pr.AuthorID,
 		Status:            string(pr.Status),
 		AssignedReviewers: reviewers,
+		Reviewers:         states,
+		ReviewerTeams:     pr.ReviewerTeams,
+		Repository:        pr.Repository,
+		ChangedFiles:      pr.ChangedFiles,
+		Labels:            pr.Labels,
+		UnmatchedLabels:   pr.UnmatchedLabels,
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
+		ClosedAt:          pr.ClosedAt,
+		MergeForced:       pr.MergeForced,
+		Version:           pr.Version,
 	}
 }
 
@@ -31,11 +52,102 @@ func ToDTOPRShort(pr review.PullRequest) resp.PullRequestShort {
 	}
 }
 
+// ToDTOEvent маппит domain.PREvent -> resp.PREvent
+func ToDTOEvent(ev review.PREvent) resp.PREvent {
+	return resp.PREvent{
+		EventID:       ev.ID,
+		Type:          string(ev.Type),
+		ActorID:       ev.ActorID,
+		OldReviewerID: ev.OldReviewerID,
+		NewReviewerID: ev.NewReviewerID,
+		Details:       ev.Details,
+		CreatedAt:     ev.CreatedAt,
+	}
+}
+
+// ToDTOReassignmentReport маппит domain.ReassignmentReport -> resp.ReassignmentReport
+func ToDTOReassignmentReport(r review.ReassignmentReport) resp.ReassignmentReport {
+	out := resp.ReassignmentReport{
+		Reassigned: make([]resp.Reassignment, 0, len(r.Reassigned)),
+		Failed:     make([]resp.ReassignmentFailure, 0, len(r.Failed)),
+	}
+	for _, ra := range r.Reassigned {
+		out.Reassigned = append(out.Reassigned, resp.Reassignment{
+			PullRequestID: ra.PRID,
+			OldUserID:     ra.OldReviewerID,
+			NewUserID:     ra.NewReviewerID,
+		})
+	}
+	for _, f := range r.Failed {
+		out.Failed = append(out.Failed, resp.ReassignmentFailure{
+			PullRequestID: f.PRID,
+			UserID:        f.ReviewerID,
+			Reason:        f.Reason,
+		})
+	}
+	return out
+}
+
 // FromCreatePRReq маппит req.CreatePR -> review.PullRequest
-func FromCreatePRReq(id string, authorID string, title string) review.PullRequest {
+func FromCreatePRReq(r req.CreatePR) review.PullRequest {
 	return review.PullRequest{
-		ID:       id,
-		Title:    title,
-		AuthorID: authorID,
+		ID:           r.PullRequestID,
+		Title:        r.PullRequestName,
+		AuthorID:     r.AuthorID,
+		Repository:   r.Repository,
+		ChangedFiles: r.ChangedFiles,
+		Labels:       r.Labels,
+	}
+}
+
+// CreatePROptionsFromReq маппит req.CreatePR -> review.CreatePROptions
+func CreatePROptionsFromReq(r req.CreatePR) review.CreatePROptions {
+	opts := review.CreatePROptions{Draft: r.Draft}
+	if r.ReviewersCount != nil {
+		opts.ReviewersCount = *r.ReviewersCount
+	}
+	return opts
+}
+
+// FromPreviewAssignmentReq маппит req.PreviewAssignment -> review.PullRequest и review.CreatePROptions
+func FromPreviewAssignmentReq(r req.PreviewAssignment) (review.PullRequest, review.CreatePROptions) {
+	pr := review.PullRequest{
+		ID:           r.PullRequestID,
+		Title:        r.PullRequestName,
+		AuthorID:     r.AuthorID,
+		Repository:   r.Repository,
+		ChangedFiles: r.ChangedFiles,
+		Labels:       r.Labels,
+	}
+	var opts review.CreatePROptions
+	if r.ReviewersCount != nil {
+		opts.ReviewersCount = *r.ReviewersCount
+	}
+	return pr, opts
+}
+
+// ToDTOAssignmentPreview маппит review.AssignmentPreview -> resp.AssignmentPreview
+func ToDTOAssignmentPreview(p review.AssignmentPreview) resp.AssignmentPreview {
+	out := resp.AssignmentPreview{
+		AuthorID:          p.AuthorID,
+		TeamName:          p.TeamName,
+		AssignedReviewers: append(make([]string, 0, len(p.ReviewerIDs)), p.ReviewerIDs...),
+		ReviewerTeams:     p.ReviewerTeams,
+		UnmatchedLabels:   p.UnmatchedLabels,
+		Candidates:        make([]resp.CandidateTrace, 0, len(p.Candidates)),
+	}
+	if p.Err != nil {
+		out.Error = p.Err.Error()
+	}
+	for _, c := range p.Candidates {
+		out.Candidates = append(out.Candidates, resp.CandidateTrace{
+			UserID:         c.UserID,
+			TeamName:       c.TeamName,
+			OpenReviews:    c.OpenReviews,
+			MaxOpenReviews: c.MaxOpenReviews,
+			Status:         string(c.Status),
+			Details:        c.Details,
+		})
 	}
+	return out
 }
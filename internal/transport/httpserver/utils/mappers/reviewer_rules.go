@@ -0,0 +1,63 @@
+package mappers
+
+import (
+	"github.com/zapevnik/pr-review-service/internal/domain/review"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/req"
+	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/resp"
+)
+
+// FromReviewerRulesReq маппит []req.ReviewerRule -> []review.ReviewerRule
+func FromReviewerRulesReq(rules []req.ReviewerRule) []review.ReviewerRule {
+	out := make([]review.ReviewerRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, review.ReviewerRule{
+			Type:       review.ReviewerRuleType(r.Type),
+			AuthorID:   r.AuthorID,
+			ReviewerID: r.ReviewerID,
+		})
+	}
+	return out
+}
+
+// ToDTOReviewerRule маппит review.ReviewerRule -> resp.ReviewerRule
+func ToDTOReviewerRule(r review.ReviewerRule) resp.ReviewerRule {
+	return resp.ReviewerRule{
+		Type:       string(r.Type),
+		AuthorID:   r.AuthorID,
+		ReviewerID: r.ReviewerID,
+	}
+}
+
+// ToDTOReviewerRules маппит правила подбора команды -> resp.ReviewerRules
+func ToDTOReviewerRules(teamName string, rules []review.ReviewerRule) resp.ReviewerRules {
+	out := resp.ReviewerRules{
+		TeamName: teamName,
+		Rules:    make([]resp.ReviewerRule, 0, len(rules)),
+	}
+	for _, r := range rules {
+		out.Rules = append(out.Rules, ToDTOReviewerRule(r))
+	}
+	return out
+}
+
+// ToDTORulesDryRun маппит review.RulesDryRun -> resp.RulesDryRun
+func ToDTORulesDryRun(d review.RulesDryRun) resp.RulesDryRun {
+	out := resp.RulesDryRun{
+		AuthorID:   d.AuthorID,
+		TeamName:   d.TeamName,
+		Candidates: make([]resp.RuleCheck, 0, len(d.Candidates)),
+	}
+	for _, c := range d.Candidates {
+		check := resp.RuleCheck{
+			UserID:   c.UserID,
+			TeamName: c.TeamName,
+			Decision: string(c.Decision),
+		}
+		if c.Rule != nil {
+			rule := ToDTOReviewerRule(*c.Rule)
+			check.Rule = &rule
+		}
+		out.Candidates = append(out.Candidates, check)
+	}
+	return out
+}
@@ -7,8 +7,8 @@ import (
 	"github.com/zapevnik/pr-review-service/internal/transport/httpserver/dto/resp"
 )
 
-// TeamAddRequestToArgs конвертирует req.TeamAdd -> имя команды + слайс domain.User
-func TeamAddRequestToArgs(r req.TeamAdd) (string, []review.User, error) {
+// TeamAddRequestToArgs конвертирует req.TeamAdd -> domain.Team + слайс domain.User
+func TeamAddRequestToArgs(r req.TeamAdd) (review.Team, []review.User, error) {
 	members := make([]review.User, 0, len(r.Members))
 
 	for _, m := range r.Members {
@@ -18,13 +18,28 @@ func TeamAddRequestToArgs(r req.TeamAdd) (string, []review.User, error) {
 		}
 
 		members = append(members, review.User{
-			ID:       id,
-			Name:     m.Username,
-			IsActive: m.IsActive,
+			ID:             id,
+			Name:           m.Username,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: m.MaxOpenReviews,
+			Seniority:      review.Seniority(m.Seniority),
 		})
 	}
 
-	return r.TeamName, members, nil
+	team := review.Team{
+		Name:           r.TeamName,
+		MinReviewers:   review.DefaultMinReviewers,
+		MaxReviewers:   review.DefaultMaxReviewers,
+		MaxOpenReviews: r.MaxOpenReviews,
+	}
+	if r.MinReviewers != nil {
+		team.MinReviewers = *r.MinReviewers
+	}
+	if r.MaxReviewers != nil {
+		team.MaxReviewers = *r.MaxReviewers
+	}
+
+	return team, members, nil
 }
 
 // TeamToResponse конвертирует domain.Team и []domain.User -> resp.Team
@@ -33,14 +48,41 @@ func TeamToResponse(team review.Team, members []review.User) resp.Team {
 
 	for _, u := range members {
 		respMembers = append(respMembers, resp.TeamMember{
-			UserID:   u.ID,
-			Username: u.Name,
-			IsActive: u.IsActive,
+			UserID:         u.ID,
+			Username:       u.Name,
+			IsActive:       u.IsActive,
+			MaxOpenReviews: u.MaxOpenReviews,
+			Tags:           u.Tags,
+			Seniority:      string(u.Seniority),
 		})
 	}
 
 	return resp.Team{
-		TeamName: team.Name,
-		Members:  respMembers,
+		TeamName:       team.Name,
+		Members:        respMembers,
+		MinReviewers:   team.MinReviewers,
+		MaxReviewers:   team.MaxReviewers,
+		MaxOpenReviews: team.MaxOpenReviews,
+	}
+}
+
+// FromRoleQuotasReq конвертирует []req.RoleQuota -> []domain.RoleQuota
+func FromRoleQuotasReq(qs []req.RoleQuota) []review.RoleQuota {
+	res := make([]review.RoleQuota, 0, len(qs))
+	for _, q := range qs {
+		res = append(res, review.RoleQuota{Seniority: review.Seniority(q.Seniority), Count: q.Count})
+	}
+	return res
+}
+
+// ToDTORoleQuotas конвертирует []domain.RoleQuota -> []resp.RoleQuota
+func ToDTORoleQuotas(qs []review.RoleQuota) []resp.RoleQuota {
+	if len(qs) == 0 {
+		return nil
+	}
+	res := make([]resp.RoleQuota, 0, len(qs))
+	for _, q := range qs {
+		res = append(res, resp.RoleQuota{Seniority: string(q.Seniority), Count: q.Count})
 	}
+	return res
 }
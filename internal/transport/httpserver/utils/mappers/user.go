@@ -9,10 +9,13 @@ import (
 // ToDTOUser маппит domain.User -> resp.User
 func ToDTOUser(u review.User) resp.User {
 	return resp.User{
-		UserID:   u.ID,
-		Username: u.Name,
-		TeamName: u.Team,
-		IsActive: u.IsActive,
+		UserID:         u.ID,
+		Username:       u.Name,
+		TeamName:       u.Team,
+		IsActive:       u.IsActive,
+		MaxOpenReviews: u.MaxOpenReviews,
+		Tags:           u.Tags,
+		Seniority:      string(u.Seniority),
 	}
 }
 
@@ -23,3 +26,33 @@ func FromSetIsActiveReq(r req.SetIsActive) review.User {
 		IsActive: r.IsActive,
 	}
 }
+
+// FromAddAbsenceReq маппит req.AddAbsence -> domain.Absence
+func FromAddAbsenceReq(r req.AddAbsence) review.Absence {
+	return review.Absence{
+		UserID:   r.UserID,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+		Reason:   r.Reason,
+	}
+}
+
+// ToDTOAbsence маппит domain.Absence -> resp.Absence
+func ToDTOAbsence(a review.Absence) resp.Absence {
+	return resp.Absence{
+		AbsenceID: a.ID,
+		UserID:    a.UserID,
+		StartsAt:  a.StartsAt,
+		EndsAt:    a.EndsAt,
+		Reason:    a.Reason,
+	}
+}
+
+// ToDTOAbsences маппит []domain.Absence -> []resp.Absence
+func ToDTOAbsences(as []review.Absence) []resp.Absence {
+	res := make([]resp.Absence, 0, len(as))
+	for _, a := range as {
+		res = append(res, ToDTOAbsence(a))
+	}
+	return res
+}
@@ -1,7 +1,11 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
 
 	"github.com/zapevnik/pr-review-service/internal/domain/review"
 
@@ -15,21 +19,65 @@ func RespondJSON(w http.ResponseWriter, status int, v any) {
 }
 
 func HandleDomainError(w http.ResponseWriter, err error) bool {
+	var blocked *review.MergeBlockedError
+	if errors.As(err, &blocked) {
+		WriteErrorDetails(w, http.StatusConflict, "MERGE_BLOCKED", "merge policy is not satisfied", blocked.Unmet)
+		return true
+	}
+
 	switch err {
 	case review.ErrTeamExists:
 		WriteError(w, http.StatusBadRequest, "TEAM_EXISTS", "team already exists")
+	case review.ErrForceNotAllowed:
+		WriteError(w, http.StatusForbidden, "FORCE_NOT_ALLOWED", "force merge requires an actor listed in review.merge.forceAllowed")
 	case review.ErrPRExists:
 		WriteError(w, http.StatusConflict, "PR_EXISTS", "pull request already exists")
 	case review.ErrPRMerged:
 		WriteError(w, http.StatusConflict, "PR_MERGED", "cannot reassign on merged PR")
+	case review.ErrPRClosed:
+		WriteError(w, http.StatusConflict, "PR_CLOSED", "pull request is closed")
+	case review.ErrPRDraft:
+		WriteError(w, http.StatusConflict, "PR_DRAFT", "pull request is a draft")
 	case review.ErrNotAssigned:
 		WriteError(w, http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
 	case review.ErrNoCandidate:
 		WriteError(w, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team")
+	case review.ErrConflict:
+		WriteError(w, http.StatusConflict, "CONFLICT", "pull request was modified concurrently, reload and retry")
 	case review.ErrNotFound:
 		WriteError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+	case review.ErrNotInTeam:
+		WriteError(w, http.StatusConflict, "NOT_IN_TEAM", "user is not a member of this team")
+	case review.ErrTeamNotEmpty:
+		WriteError(w, http.StatusConflict, "TEAM_NOT_EMPTY", "team still has members")
 	case review.ErrUserInAnotherTeam:
 		WriteError(w, http.StatusBadRequest, "USER_IN_ANOTHER_TEAM", "user already belongs to another team")
+	case review.ErrInvalidReviewersCount:
+		WriteError(w, http.StatusBadRequest, "INVALID_REVIEWERS_COUNT", "invalid reviewers count")
+	case review.ErrInvalidReviewState:
+		WriteError(w, http.StatusBadRequest, "INVALID_REVIEW_STATE", "state must be one of APPROVED, CHANGES_REQUESTED, COMMENTED")
+	case review.ErrInvalidAbsence:
+		WriteError(w, http.StatusBadRequest, "INVALID_ABSENCE", "ends_at must be after starts_at")
+	case review.ErrInvalidMaxOpenReviews:
+		WriteError(w, http.StatusBadRequest, "INVALID_MAX_OPEN_REVIEWS", "max_open_reviews must not be negative")
+	case review.ErrInvalidFallbackTeams:
+		WriteError(w, http.StatusBadRequest, "INVALID_FALLBACK_TEAMS", "fallback teams must be distinct and differ from the team itself")
+	case review.ErrInvalidOwnershipRules:
+		WriteError(w, http.StatusBadRequest, "INVALID_OWNERSHIP_RULES", "ownership rules must have valid patterns and distinct owners")
+	case review.ErrInvalidChangedFiles:
+		WriteError(w, http.StatusBadRequest, "INVALID_CHANGED_FILES", "changed_files must contain non-empty paths")
+	case review.ErrInvalidTags:
+		WriteError(w, http.StatusBadRequest, "INVALID_TAGS", "tags and labels must be short lowercase words like go, sql or c++")
+	case review.ErrInvalidSeniority:
+		WriteError(w, http.StatusBadRequest, "INVALID_SENIORITY", "seniority must be one of JUNIOR, MIDDLE, SENIOR")
+	case review.ErrInvalidReviewerRules:
+		WriteError(w, http.StatusBadRequest, "INVALID_REVIEWER_RULES", "rules must be EXCLUDE or PREFER for distinct author/reviewer pairs")
+	case review.ErrInvalidRoleQuotas:
+		WriteError(w, http.StatusBadRequest, "INVALID_ROLE_QUOTAS", "role quotas must have distinct seniorities, positive counts and fit into max_reviewers")
+	case review.ErrAllReviewersAtCapacity:
+		WriteError(w, http.StatusConflict, "ALL_REVIEWERS_AT_CAPACITY", "all candidate reviewers reached their open reviews limit")
+	case review.ErrNotEnoughReviewers:
+		WriteError(w, http.StatusConflict, "NOT_ENOUGH_REVIEWERS", "not enough active reviewers in team to meet the minimum")
 	default:
 		return false
 	}
@@ -46,3 +94,37 @@ func WriteError(w http.ResponseWriter, status int, code, message string) {
 		},
 	})
 }
+
+func WriteErrorDetails(w http.ResponseWriter, status int, code, message string, details []string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"details": details,
+		},
+	})
+}
+
+// WithExpectedVersion возвращает контекст запроса с ожидаемой версией PR из поля
+// expected_version тела или заголовка If-Match (поле тела имеет приоритет).
+// If-Match: * и отсутствие обоих значений версию не ограничивают.
+func WithExpectedVersion(r *http.Request, bodyVersion *int64) (context.Context, error) {
+	ctx := r.Context()
+	if bodyVersion != nil {
+		return review.WithExpectedVersion(ctx, *bodyVersion), nil
+	}
+
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+	if tag == "" || tag == "*" {
+		return ctx, nil
+	}
+	tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+
+	v, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil {
+		return ctx, errors.New("If-Match must contain a pull request version")
+	}
+	return review.WithExpectedVersion(ctx, v), nil
+}
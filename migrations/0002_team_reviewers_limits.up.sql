@@ -0,0 +1,13 @@
+-- Команды, созданные до появления лимитов, получают min_reviewers = 0:
+-- раньше PR создавался и без ревьюверов, и это поведение для них сохраняется.
+-- Новые команды получают min_reviewers = 1, если его не передали явно.
+ALTER TABLE teams
+  ADD COLUMN min_reviewers INT NOT NULL DEFAULT 0,
+  ADD COLUMN max_reviewers INT NOT NULL DEFAULT 2;
+
+ALTER TABLE teams
+  ALTER COLUMN min_reviewers SET DEFAULT 1;
+
+ALTER TABLE teams
+  ADD CONSTRAINT teams_reviewers_limits_check
+  CHECK (min_reviewers >= 0 AND max_reviewers >= 1 AND min_reviewers <= max_reviewers);
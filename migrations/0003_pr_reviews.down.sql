@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pr_reviews_pr;
+
+DROP TABLE IF EXISTS pr_reviews;
@@ -0,0 +1,10 @@
+CREATE TABLE pr_reviews (
+  review_id BIGSERIAL PRIMARY KEY,
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  reviewer_id TEXT NOT NULL REFERENCES users(user_id),
+  review_state TEXT NOT NULL,
+  review_body TEXT NOT NULL DEFAULT '',
+  submitted_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_pr_reviews_pr ON pr_reviews(pr_id, submitted_at);
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+  ADD COLUMN merge_forced BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,12 @@
+CREATE TABLE pr_events (
+  event_id BIGSERIAL PRIMARY KEY,
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  event_type TEXT NOT NULL,
+  actor_id TEXT NOT NULL DEFAULT '',
+  old_reviewer_id TEXT,
+  new_reviewer_id TEXT,
+  details TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_pr_events_pr ON pr_events(pr_id, event_id);
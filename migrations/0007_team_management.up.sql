@@ -0,0 +1,9 @@
+ALTER TABLE users
+  ALTER COLUMN team_name DROP NOT NULL;
+
+ALTER TABLE users
+  DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+
+ALTER TABLE users
+  ADD CONSTRAINT users_team_name_fkey
+  FOREIGN KEY (team_name) REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE RESTRICT;
@@ -0,0 +1,10 @@
+CREATE TABLE user_absences (
+  absence_id BIGSERIAL PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  starts_at TIMESTAMPTZ NOT NULL,
+  ends_at TIMESTAMPTZ NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  CONSTRAINT user_absences_period_check CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_user_absences_user ON user_absences(user_id, ends_at);
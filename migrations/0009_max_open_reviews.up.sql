@@ -0,0 +1,7 @@
+ALTER TABLE teams
+  ADD COLUMN max_open_reviews INTEGER
+    CONSTRAINT teams_max_open_reviews_check CHECK (max_open_reviews > 0);
+
+ALTER TABLE users
+  ADD COLUMN max_open_reviews INTEGER
+    CONSTRAINT users_max_open_reviews_check CHECK (max_open_reviews > 0);
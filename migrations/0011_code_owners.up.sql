@@ -0,0 +1,24 @@
+ALTER TABLE pull_requests ADD COLUMN repository TEXT;
+
+CREATE TABLE pr_files (
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  file_path TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (pr_id, file_path)
+);
+
+CREATE TABLE code_owner_rules (
+  repository TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  pattern TEXT NOT NULL,
+  PRIMARY KEY (repository, position)
+);
+
+CREATE TABLE code_owner_rule_owners (
+  repository TEXT NOT NULL,
+  rule_position INTEGER NOT NULL,
+  user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (repository, rule_position, user_id),
+  FOREIGN KEY (repository, rule_position) REFERENCES code_owner_rules(repository, position) ON DELETE CASCADE
+);
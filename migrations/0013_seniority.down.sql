@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS team_role_quotas;
+
+ALTER TABLE users DROP COLUMN IF EXISTS seniority;
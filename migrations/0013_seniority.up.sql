@@ -0,0 +1,11 @@
+ALTER TABLE users
+  ADD COLUMN seniority TEXT
+    CONSTRAINT users_seniority_check CHECK (seniority IN ('JUNIOR', 'MIDDLE', 'SENIOR'));
+
+CREATE TABLE team_role_quotas (
+  team_name TEXT NOT NULL REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+  seniority TEXT NOT NULL CHECK (seniority IN ('JUNIOR', 'MIDDLE', 'SENIOR')),
+  reviewers_count INTEGER NOT NULL CHECK (reviewers_count > 0),
+  position INTEGER NOT NULL,
+  PRIMARY KEY (team_name, seniority)
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS pr_events;
+DROP TABLE IF EXISTS pr_reviews;
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
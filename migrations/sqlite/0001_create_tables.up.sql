@@ -0,0 +1,64 @@
+CREATE TABLE teams (
+  team_name TEXT PRIMARY KEY,
+  min_reviewers INTEGER NOT NULL DEFAULT 1,
+  max_reviewers INTEGER NOT NULL DEFAULT 2,
+  CONSTRAINT teams_reviewers_limits_check
+    CHECK (min_reviewers >= 0 AND max_reviewers >= 1 AND min_reviewers <= max_reviewers)
+);
+
+CREATE TABLE users (
+  user_id TEXT PRIMARY KEY,
+  team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+  user_name TEXT NOT NULL,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE pull_requests (
+  pr_id TEXT PRIMARY KEY,
+  pr_title TEXT NOT NULL,
+  author_id TEXT NOT NULL REFERENCES users(user_id),
+  pr_status TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  merged_at TIMESTAMP,
+  closed_at TIMESTAMP,
+  merge_forced BOOLEAN NOT NULL DEFAULT FALSE,
+  pr_version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE pr_reviewers (
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  reviewer_id TEXT NOT NULL REFERENCES users(user_id),
+  PRIMARY KEY (pr_id, reviewer_id)
+);
+
+CREATE TABLE pr_reviews (
+  review_id INTEGER PRIMARY KEY AUTOINCREMENT,
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  reviewer_id TEXT NOT NULL REFERENCES users(user_id),
+  review_state TEXT NOT NULL,
+  review_body TEXT NOT NULL DEFAULT '',
+  submitted_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE pr_events (
+  event_id INTEGER PRIMARY KEY AUTOINCREMENT,
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  event_type TEXT NOT NULL,
+  actor_id TEXT NOT NULL DEFAULT '',
+  old_reviewer_id TEXT,
+  new_reviewer_id TEXT,
+  details TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_users_team ON users(team_name);
+CREATE INDEX idx_users_active_team ON users(team_name, is_active);
+
+CREATE INDEX idx_pull_requests_author ON pull_requests(author_id);
+CREATE INDEX idx_pull_requests_status ON pull_requests(pr_status);
+
+CREATE INDEX idx_pr_reviewers_reviewer ON pr_reviewers(reviewer_id);
+
+CREATE INDEX idx_pr_reviews_pr ON pr_reviews(pr_id, submitted_at);
+
+CREATE INDEX idx_pr_events_pr ON pr_events(pr_id, event_id);
@@ -0,0 +1,17 @@
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TABLE users_old (
+  user_id TEXT PRIMARY KEY,
+  team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+  user_name TEXT NOT NULL,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+INSERT INTO users_old (user_id, team_name, user_name, is_active)
+SELECT user_id, team_name, user_name, is_active FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
+
+CREATE INDEX idx_users_team ON users(team_name);
+CREATE INDEX idx_users_active_team ON users(team_name, is_active);
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN max_open_reviews;
+
+ALTER TABLE teams DROP COLUMN max_open_reviews;
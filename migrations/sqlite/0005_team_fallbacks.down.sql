@@ -0,0 +1,19 @@
+-- SQLite не удаляет столбцы с внешним ключом, поэтому pr_reviewers пересоздаётся.
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TABLE pr_reviewers_old (
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  reviewer_id TEXT NOT NULL REFERENCES users(user_id),
+  PRIMARY KEY (pr_id, reviewer_id)
+);
+
+INSERT INTO pr_reviewers_old (pr_id, reviewer_id)
+SELECT pr_id, reviewer_id FROM pr_reviewers;
+
+DROP TABLE pr_reviewers;
+
+ALTER TABLE pr_reviewers_old RENAME TO pr_reviewers;
+
+CREATE INDEX idx_pr_reviewers_reviewer ON pr_reviewers(reviewer_id);
+
+DROP TABLE IF EXISTS team_fallbacks;
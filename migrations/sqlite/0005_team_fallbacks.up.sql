@@ -0,0 +1,10 @@
+CREATE TABLE team_fallbacks (
+  team_name TEXT NOT NULL REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+  fallback_team TEXT NOT NULL REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (team_name, fallback_team),
+  CONSTRAINT team_fallbacks_self_check CHECK (team_name <> fallback_team)
+);
+
+ALTER TABLE pr_reviewers
+  ADD COLUMN source_team TEXT REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE SET NULL;
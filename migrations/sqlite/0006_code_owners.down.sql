@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS code_owner_rule_owners;
+DROP TABLE IF EXISTS code_owner_rules;
+DROP TABLE IF EXISTS pr_files;
+
+ALTER TABLE pull_requests DROP COLUMN repository;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pr_labels;
+DROP TABLE IF EXISTS user_tags;
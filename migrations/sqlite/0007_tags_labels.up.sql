@@ -0,0 +1,12 @@
+CREATE TABLE user_tags (
+  user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  tag TEXT NOT NULL,
+  PRIMARY KEY (user_id, tag)
+);
+
+CREATE TABLE pr_labels (
+  pr_id TEXT NOT NULL REFERENCES pull_requests(pr_id) ON DELETE CASCADE,
+  label TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (pr_id, label)
+);
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN seniority TEXT CHECK (seniority IN ('JUNIOR', 'MIDDLE', 'SENIOR'));
+
+CREATE TABLE team_role_quotas (
+  team_name TEXT NOT NULL REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+  seniority TEXT NOT NULL CHECK (seniority IN ('JUNIOR', 'MIDDLE', 'SENIOR')),
+  reviewers_count INTEGER NOT NULL CHECK (reviewers_count > 0),
+  position INTEGER NOT NULL,
+  PRIMARY KEY (team_name, seniority)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE reviewer_rules (
+  team_name TEXT NOT NULL REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+  author_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  reviewer_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  rule_type TEXT NOT NULL CHECK (rule_type IN ('EXCLUDE', 'PREFER')),
+  position INTEGER NOT NULL,
+  PRIMARY KEY (team_name, author_id, reviewer_id),
+  CONSTRAINT reviewer_rules_self_check CHECK (author_id <> reviewer_id)
+);
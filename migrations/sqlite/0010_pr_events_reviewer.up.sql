@@ -0,0 +1 @@
+CREATE INDEX idx_pr_events_new_reviewer ON pr_events(new_reviewer_id, event_id);
@@ -0,0 +1,21 @@
+ALTER TABLE pull_requests
+  ADD COLUMN closed_from TEXT CHECK (closed_from IN ('OPEN', 'DRAFT'));
+
+-- Для уже закрытых PR статус до закрытия восстанавливается по истории:
+-- последнее событие, меняющее статус открытого PR (CREATED или
+-- READY_FOR_REVIEW), даёт DRAFT для созданного черновиком и ещё не
+-- переведённого PR, иначе OPEN. CLOSED/REOPENED статус между ними не меняют.
+-- PR без истории считаются открытыми.
+UPDATE pull_requests
+   SET closed_from = COALESCE((
+         SELECT CASE
+                  WHEN e.event_type = 'CREATED' AND e.details = 'draft' THEN 'DRAFT'
+                  ELSE 'OPEN'
+                END
+           FROM pr_events e
+          WHERE e.pr_id = pull_requests.pr_id
+            AND e.event_type IN ('CREATED', 'READY_FOR_REVIEW')
+          ORDER BY e.event_id DESC
+          LIMIT 1
+       ), 'OPEN')
+ WHERE pr_status = 'CLOSED';